@@ -0,0 +1,226 @@
+package calendar
+
+import (
+	"fmt"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/logger"
+	"github.com/elizavetanr/myDays/reminder"
+	"sort"
+	"time"
+)
+
+// PendingReminder - сработавшее, но еще не подтвержденное напоминание.
+type PendingReminder struct {
+	Event    *events.Event
+	Reminder *reminder.Reminder
+}
+
+// SetRepeatInterval задает интервал повтора неподтвержденных напоминаний
+// событий с высоким приоритетом. Нулевой интервал отключает повтор.
+func (c *Calendar) SetRepeatInterval(d time.Duration) error {
+	if d < 0 {
+		return ErrInvalidDuration
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.repeatInterval = d
+	return nil
+}
+
+func (c *Calendar) AcknowledgeReminder(reminderID string) (*PendingReminder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, r, err := c.findReminder(reminderID)
+	if err != nil {
+		return nil, fmt.Errorf("невозможно подтвердить напоминание: %w", err)
+	}
+	if err := r.Acknowledge(); err != nil {
+		return nil, fmt.Errorf("невозможно подтвердить напоминание: %w", err)
+	}
+	c.scheduler.Cancel(r.ID)
+	c.dnd.Drop(r.ID)
+	if err := c.inbox.Acknowledge(r.ID); err != nil {
+		logger.Error(err.Error())
+	}
+	return &PendingReminder{Event: e.Clone(), Reminder: r.Clone()}, nil
+}
+
+func (c *Calendar) SnoozeReminder(reminderID string, d time.Duration) (*PendingReminder, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("невозможно отложить напоминание: %w", ErrInvalidDuration)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, r, err := c.findReminder(reminderID)
+	if err != nil {
+		return nil, fmt.Errorf("невозможно отложить напоминание: %w", err)
+	}
+	if err := r.Snooze(c.clock.Now().Add(d)); err != nil {
+		return nil, fmt.Errorf("невозможно отложить напоминание: %w", err)
+	}
+	c.dnd.Drop(r.ID)
+	if err := c.scheduleReminder(e, r); err != nil {
+		return nil, fmt.Errorf("невозможно отложить напоминание: %w", err)
+	}
+	return &PendingReminder{Event: e.Clone(), Reminder: r.Clone()}, nil
+}
+
+func (c *Calendar) PendingReminders() []PendingReminder {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var pending []PendingReminder
+	for _, e := range c.sortedEvents() {
+		for _, r := range e.Reminders {
+			if r.IsPending() {
+				pending = append(pending, PendingReminder{Event: e.Clone(), Reminder: r.Clone()})
+			}
+		}
+	}
+	return pending
+}
+
+// UpcomingReminders возвращает напоминания неотмененных событий, которые
+// еще предстоит отправить, в порядке времени срабатывания.
+func (c *Calendar) UpcomingReminders() []PendingReminder {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	now := c.clock.Now()
+	var upcoming []PendingReminder
+	for _, e := range c.activeEvents() {
+		for _, r := range e.Reminders {
+			if !r.Sent && !r.Missed && !r.Acknowledged && r.NextAt().After(now) {
+				upcoming = append(upcoming, PendingReminder{Event: e.Clone(), Reminder: r.Clone()})
+			}
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].Reminder.NextAt().Before(upcoming[j].Reminder.NextAt())
+	})
+	return upcoming
+}
+
+// fireTolerance - насколько раньше своего времени внешний таймер может
+// отправить напоминание: at и systemd срабатывают с точностью до минуты.
+const fireTolerance = time.Minute
+
+// FireReminder отправляет напоминание немедленно, не дожидаясь планировщика.
+// Используется внешними таймерами, когда приложение не запущено.
+// Уже отправленное напоминание повторно не отправляется. Если время
+// напоминания еще не наступило, например событие перенесли или напоминание
+// отложили после создания таймера, возвращается ErrReminderNotDue.
+func (c *Calendar) FireReminder(reminderID string) error {
+	c.mu.RLock()
+	e, r, err := c.findReminder(reminderID)
+	cancelled := err == nil && e.Cancelled
+	notDue := err == nil && c.clock.Now().Add(fireTolerance).Before(r.NextAt())
+	c.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("невозможно отправить напоминание: %w", err)
+	}
+	if cancelled {
+		return fmt.Errorf("невозможно отправить напоминание: %w", ErrEventCancelled)
+	}
+	if notDue {
+		return fmt.Errorf("невозможно отправить напоминание %s: %w", reminderID, ErrReminderNotDue)
+	}
+	c.scheduler.Cancel(r.ID)
+	c.fire(e, r)
+	return nil
+}
+
+func (c *Calendar) scheduleReminder(e *events.Event, r *reminder.Reminder) error {
+	if e.Cancelled {
+		return fmt.Errorf("невозможно запустить напоминание: %w", ErrEventCancelled)
+	}
+	return r.Schedule(c.scheduler, func() { c.fire(e, r) })
+}
+
+// fire отправляет напоминание. Отправленным оно отмечается только при
+// доставке, а не при задержке режимом тишины, см. send. Доставка
+// выполняется без блокировки, чтобы медленные способы доставки
+// не задерживали работу с календарем.
+func (c *Calendar) fire(e *events.Event, r *reminder.Reminder) {
+	c.mu.Lock()
+	var notifications []reminder.Notification
+	if c.isLive(e, r) && !r.Sent {
+		c.collect(e, r, &notifications)(r.Message)
+	}
+	c.mu.Unlock()
+	for _, n := range notifications {
+		c.deliver(n)
+	}
+}
+
+// markSent отмечает напоминание отправленным и, если событие важное,
+// планирует его повтор до подтверждения. Возвращает false, если
+// напоминание уже не нужно отправлять: его удалили, подтвердили
+// или событие отменили, пока оно ждало окончания тишины.
+func (c *Calendar) markSent(reminderID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, r, err := c.findReminder(reminderID)
+	if err != nil || e.Cancelled || r.Acknowledged {
+		return false
+	}
+	if !r.Sent {
+		r.MarkSent()
+		c.scheduleRepeat(e, r)
+	}
+	return true
+}
+
+func (c *Calendar) scheduleRepeat(e *events.Event, r *reminder.Reminder) {
+	if c.repeatInterval <= 0 || e.Priority != events.PriorityHigh || !r.IsPending() {
+		return
+	}
+	c.scheduler.Schedule(r.ID, c.clock.Now().Add(c.repeatInterval), func() {
+		c.mu.Lock()
+		var notifications []reminder.Notification
+		if c.isLive(e, r) {
+			r.Repeat(c.collect(e, r, &notifications))
+			c.scheduleRepeat(e, r)
+		}
+		c.mu.Unlock()
+		for _, n := range notifications {
+			c.deliver(n)
+		}
+	})
+}
+
+// isLive сообщает, что напоминание все еще принадлежит событию календаря:
+// задание планировщика могло сработать одновременно с удалением.
+func (c *Calendar) isLive(e *events.Event, r *reminder.Reminder) bool {
+	if c.calendarEvents[e.ID] != e || e.Cancelled {
+		return false
+	}
+	found, err := e.FindReminder(r.ID)
+	return err == nil && found == r
+}
+
+// collect возвращает функцию, которая складывает текст напоминания в список
+// уведомлений для последующей доставки всеми способами, настроенными
+// для приоритета события, с учетом режима тишины. Шаблон текста заполняется
+// в момент отправки, чтобы значения были актуальными.
+func (c *Calendar) collect(e *events.Event, r *reminder.Reminder, notifications *[]reminder.Notification) func(string) {
+	return func(msg string) {
+		now := c.clock.Now()
+		*notifications = append(*notifications, reminder.Notification{
+			ReminderID: r.ID,
+			EventID:    e.ID,
+			Title:      e.Title,
+			StartAt:    e.StartAt,
+			Priority:   string(e.Priority),
+			Message:    reminder.RenderMessage(msg, e.TemplateData(now)),
+			FiredAt:    now,
+		})
+	}
+}
+
+func (c *Calendar) findReminder(reminderID string) (*events.Event, *reminder.Reminder, error) {
+	for _, e := range c.calendarEvents {
+		if r, err := e.FindReminder(reminderID); err == nil {
+			return e, r, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%s: %w", reminderID, reminder.ErrNotExistReminder)
+}
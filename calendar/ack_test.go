@@ -0,0 +1,117 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/reminder"
+	"testing"
+	"time"
+)
+
+func TestSnoozeAndAcknowledge(t *testing.T) {
+	c := NewCalendar(nil)
+	added, _ := c.AddEvent("Планерка", time.Now().Add(time.Hour).Format("2006-01-02 15:04:05"), "low", 0)
+	e := c.calendarEvents[added.ID]
+	r, _ := e.AddReminder("Скоро планерка", time.Now().Add(20*time.Millisecond))
+
+	if _, err := c.AcknowledgeReminder(r.ID); !errors.Is(err, reminder.ErrReminderNotFired) {
+		t.Errorf("Expected ErrReminderNotFired, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Start(ctx)
+	receive := func() {
+		t.Helper()
+		select {
+		case <-c.Notification:
+		case <-time.After(time.Second):
+			t.Fatal("Expected reminder to fire")
+		}
+	}
+	receive()
+	if pending := c.PendingReminders(); len(pending) != 1 {
+		t.Fatalf("Expected one pending reminder, got %d", len(pending))
+	}
+
+	snoozed, err := c.SnoozeReminder(r.ID, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected no error for snooze, got %v", err)
+	}
+	if snoozed.Reminder.Sent || snoozed.Reminder.SnoozedUntil.IsZero() {
+		t.Errorf("Expected snoozed reminder to wait, got %+v", snoozed.Reminder)
+	}
+	receive()
+
+	if _, err := c.AcknowledgeReminder(r.ID); err != nil {
+		t.Fatalf("Expected no error for ack, got %v", err)
+	}
+	if pending := c.PendingReminders(); len(pending) != 0 {
+		t.Errorf("Expected no pending reminders after ack, got %d", len(pending))
+	}
+}
+
+func TestDeliveredRemindersAreRecordedInInbox(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(2*time.Hour).Format(events.DateFormat), "low", 0)
+	r, _ := c.SetEventReminder(e.ID, "Скоро планерка", "1h")
+	c.Start(context.Background())
+
+	clock.Advance(time.Hour)
+	expectNotification(t, c, "Скоро планерка [ID: "+r.ID+"]")
+	// запись во входящие появляется сразу после доставки в терминал
+	deadline := time.Now().Add(time.Second)
+	for len(c.Inbox().History(time.Time{}, time.Time{})) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	c.AcknowledgeReminder(r.ID)
+
+	history := c.Inbox().History(time.Time{}, time.Time{})
+	if len(history) != 1 {
+		t.Fatalf("Expected one inbox entry, got %d", len(history))
+	}
+	entry := history[0]
+	if entry.EventID != e.ID || !entry.FiredAt.Equal(clockStart.Add(time.Hour)) || entry.Channels[0] != "terminal" || !entry.Acknowledged {
+		t.Errorf("Expected acknowledged terminal entry for the event, got %+v", entry)
+	}
+}
+
+func TestFireReminderWithoutScheduler(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(2*time.Hour).Format(events.DateFormat), "low", 0)
+	r, _ := c.SetEventReminder(e.ID, "Скоро планерка", "1h")
+	if upcoming := c.UpcomingReminders(); len(upcoming) != 1 || upcoming[0].Reminder.ID != r.ID {
+		t.Fatalf("Expected one upcoming reminder, got %v", upcoming)
+	}
+
+	clock.Advance(time.Hour - 30*time.Second)
+	go c.FireReminder(r.ID)
+	expectNotification(t, c, "Скоро планерка [ID: "+r.ID+"]")
+	if upcoming := c.UpcomingReminders(); len(upcoming) != 0 {
+		t.Errorf("Expected fired reminder not to be upcoming, got %v", upcoming)
+	}
+	if err := c.FireReminder("unknown"); !errors.Is(err, reminder.ErrNotExistReminder) {
+		t.Errorf("Expected ErrNotExistReminder, got %v", err)
+	}
+}
+
+// TestFireReminderRefusesBeforeItsTime проверяет, что таймер, созданный до
+// переноса события, не отправляет напоминание по старому времени.
+func TestFireReminderRefusesBeforeItsTime(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(2*time.Hour).Format(events.DateFormat), "low", 0)
+	r, _ := c.SetEventReminder(e.ID, "Скоро планерка", "1h")
+	later := clockStart.Add(74 * time.Hour).Format(events.DateFormat)
+	if _, err := c.PatchEvent(e.ID, events.EventPatch{Date: &later}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	clock.Advance(time.Hour)
+	if err := c.FireReminder(r.ID); !errors.Is(err, ErrReminderNotDue) {
+		t.Errorf("Expected ErrReminderNotDue, got %v", err)
+	}
+	if reminders := c.GetEvent()[e.ID].Reminders; reminders[0].Sent {
+		t.Error("Expected moved reminder not to be sent by the old timer")
+	}
+}
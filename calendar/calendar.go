@@ -1,11 +1,18 @@
 package calendar
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/logger"
+	"github.com/elizavetanr/myDays/reminder"
 	"github.com/elizavetanr/myDays/storage"
+	"github.com/elizavetanr/myDays/timeutil"
+	"slices"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -19,16 +26,46 @@ var (
 	ErrUnmarshalFailed        = errors.New("десериализация не выполнена")
 	ErrCalendarSaveFailed     = errors.New("сохранение данных в файл не выполнено")
 	ErrCalendarLoadFailed     = errors.New("загрузка данных из файла не выполнена")
+	ErrReminderNotSpecified   = errors.New("у события несколько напоминаний, укажите ID напоминания")
+	ErrEventCancelled         = errors.New("событие отменено")
+	ErrReminderNotDue         = errors.New("время напоминания еще не наступило")
 )
 
+// Calendar безопасен для одновременного использования: все события и их
+// напоминания изменяются только под mu, в том числе из горутины планировщика,
+// а наружу отдаются их копии.
 type Calendar struct {
-	calendarEvents map[string]*events.Event
-	storage        storage.Store
-	Notification   chan string
+	mu               sync.RWMutex
+	cancel           context.CancelFunc
+	clock            timeutil.Clock
+	calendarEvents   map[string]*events.Event
+	storage          storage.Store
+	conflictPolicy   ConflictPolicy
+	defaultDuration  time.Duration
+	workingHours     WorkingHours
+	scheduler        *reminder.Scheduler
+	graceWindow      time.Duration
+	repeatInterval   time.Duration
+	notifiers        *reminder.Registry
+	dnd              *reminder.DoNotDisturb
+	inbox            *reminder.Inbox
+	defaultReminders map[events.Priority][]time.Duration
+	digestAt         time.Duration
+	digestTomorrow   bool
+	Notification     chan Alert
+}
+
+// Alert - сообщение для вывода в терминал. Priority пуст для сообщений,
+// не относящихся к конкретному событию, например для сводки.
+type Alert struct {
+	Text     string
+	Priority events.Priority
 }
 
 func (c *Calendar) Save() error {
+	c.mu.RLock()
 	data, err := json.Marshal(c.calendarEvents)
+	c.mu.RUnlock()
 	if err != nil {
 		return fmt.Errorf("не удалось сохранить календарь: %w", ErrMarshalFailed)
 	}
@@ -44,6 +81,8 @@ func (c *Calendar) Load() error {
 	if err != nil {
 		return fmt.Errorf("не удалось загрузить календарь: %w", ErrCalendarLoadFailed)
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	err = json.Unmarshal(data, &c.calendarEvents)
 	if err != nil {
 		return fmt.Errorf("не удалось загрузить календарь: %w", ErrUnmarshalFailed)
@@ -51,103 +90,388 @@ func (c *Calendar) Load() error {
 	return nil
 }
 
+// NewCalendar создает календарь, в котором напоминания по умолчанию
+// доставляются в терминал через канал Notification.
 func NewCalendar(s storage.Store) *Calendar {
-	return &Calendar{
-		calendarEvents: make(map[string]*events.Event),
-		storage:        s,
-		Notification:   make(chan string),
+	return NewCalendarWithClock(s, timeutil.SystemClock)
+}
+
+// NewCalendarWithClock создает календарь, который отсчитывает время
+// напоминаний по clock.
+func NewCalendarWithClock(s storage.Store, clock timeutil.Clock) *Calendar {
+	c := &Calendar{
+		clock:           clock,
+		calendarEvents:  make(map[string]*events.Event),
+		storage:         s,
+		conflictPolicy:  ConflictWarn,
+		defaultDuration: time.Hour,
+		workingHours:    DefaultWorkingHours,
+		scheduler:       reminder.NewSchedulerWithClock(clock),
+		graceWindow:     time.Hour,
+		notifiers:       reminder.NewRegistry(),
+		dnd:             reminder.NewDoNotDisturb(),
+		inbox:           reminder.NewInbox(nil),
+		digestAt:        -1,
+		Notification:    make(chan Alert),
 	}
+	terminal := reminder.NewTerminalNotifier(func(text, priority string) {
+		c.Notification <- Alert{Text: text, Priority: events.Priority(priority)}
+	})
+	c.notifiers.Register(terminal)
+	c.notifiers.SetDefaultRoute(terminal.Name())
+	c.notifiers.OnDelivered(c.recordDelivery)
+	return c
+}
+
+// Now возвращает текущее время по часам календаря.
+func (c *Calendar) Now() time.Time {
+	return c.clock.Now()
+}
+
+// Notifiers возвращает реестр способов доставки напоминаний для настройки.
+func (c *Calendar) Notifiers() *reminder.Registry {
+	return c.notifiers
 }
 
-func (c *Calendar) AddEvent(title string, date string, priority events.Priority) (*events.Event, error) {
+func (c *Calendar) AddEvent(title string, date string, priority events.Priority, duration time.Duration) (*events.Event, error) {
 	event, err := events.NewEvent(title, date, priority)
 	if err != nil {
 		return nil, fmt.Errorf("невозможно добавить событие: %w", err)
 	}
+	if err := events.ValidateDuration(duration); err != nil {
+		return nil, fmt.Errorf("невозможно добавить событие: %w", err)
+	}
+	event.Duration = duration
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conflictPolicy == ConflictBlock {
+		if conflicts := c.findConflicts(event); len(conflicts) > 0 {
+			return nil, fmt.Errorf("невозможно добавить событие: %w", &ConflictError{Conflicts: cloneEvents(conflicts)})
+		}
+	}
 	c.calendarEvents[event.ID] = event
-	return event, nil
+	c.applyDefaultReminders(event)
+	return event.Clone(), nil
 }
 func (c *Calendar) DeleteEvent(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if !c.idExists(id) {
 		return fmt.Errorf("невозможно удалить событие: %w", ErrEventNotFound)
 	}
 
+	c.calendarEvents[id].CancelReminders(c.scheduler)
+	c.dropHeld(c.calendarEvents[id])
 	delete(c.calendarEvents, id)
 	return nil
 }
 
-func (c *Calendar) EditEvent(id, newTitle, newDate string, priority events.Priority) error {
+// CancelEvent отмечает событие отмененным: оно остается в календаре,
+// но не участвует в поиске пересечений и его напоминания не отправляются.
+func (c *Calendar) CancelEvent(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if !c.idExists(id) {
-		return fmt.Errorf("невозможно отредактировать событие: %w", ErrEventNotFound)
+		return fmt.Errorf("невозможно отменить событие: %w", ErrEventNotFound)
 	}
-	if err := c.calendarEvents[id].Update(newTitle, newDate, priority); err != nil {
-		return fmt.Errorf("невозможно отредактировать событие: %w", err)
+	e := c.calendarEvents[id]
+	if e.Cancelled {
+		return fmt.Errorf("невозможно отменить событие: %w", ErrEventCancelled)
 	}
+	e.Cancelled = true
+	e.CancelReminders(c.scheduler)
+	c.dropHeld(e)
 	return nil
 }
 
-func (c *Calendar) GetEvent() map[string]*events.Event {
-	return c.calendarEvents
+// dropHeld убирает из очереди тишины задержанные напоминания события.
+func (c *Calendar) dropHeld(e *events.Event) {
+	for _, r := range e.Reminders {
+		c.dnd.Drop(r.ID)
+	}
+}
+
+func (c *Calendar) EditEvent(id, newTitle, newDate string, priority events.Priority) error {
+	_, err := c.PatchEvent(id, events.EventPatch{Title: &newTitle, Date: &newDate, Priority: &priority})
+	return err
 }
 
-func (c *Calendar) SetEventReminder(id, message, before string) error {
+// EditResult описывает результат редактирования. При переносе события
+// относительные напоминания пересчитываются: Rescheduled - перезапущенные,
+// Stale - те, чье новое время уже прошло, они не запускаются.
+type EditResult struct {
+	Event       *events.Event
+	Changes     []events.FieldChange
+	Rescheduled []*reminder.Reminder
+	Stale       []*reminder.Reminder
+	AutoAdded   []*reminder.Reminder
+	AutoRemoved []*reminder.Reminder
+}
+
+func (c *Calendar) PatchEvent(id string, patch events.EventPatch) (*EditResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if !c.idExists(id) {
-		return fmt.Errorf("невозможно назначить напоминание событию: %w", ErrEventNotFound)
+		return nil, fmt.Errorf("невозможно отредактировать событие: %w", ErrEventNotFound)
 	}
-	reminderAt, err := c.calculateReminderTime(id, before)
+	e := c.calendarEvents[id]
+	candidate := *e
+	changes, err := candidate.Patch(patch)
 	if err != nil {
-		return fmt.Errorf("невозможно назначить напоминание событию: %w", err)
+		return nil, fmt.Errorf("невозможно отредактировать событие: %w", err)
+	}
+	if c.conflictPolicy == ConflictBlock && changesField(changes, "date", "duration") {
+		if conflicts := c.findConflicts(&candidate); len(conflicts) > 0 {
+			return nil, fmt.Errorf("невозможно отредактировать событие: %w", &ConflictError{Conflicts: cloneEvents(conflicts)})
+		}
 	}
+	*e = candidate
+	result := &EditResult{Changes: changes}
+	now := c.clock.Now()
+	for _, r := range e.FollowStart() {
+		c.scheduler.Cancel(r.ID)
+		c.dnd.Drop(r.ID)
+		if r.At.Before(now) {
+			r.Missed = true
+			result.Stale = append(result.Stale, r)
+			continue
+		}
+		if err := c.scheduleReminder(e, r); err == nil {
+			result.Rescheduled = append(result.Rescheduled, r)
+		}
+	}
+	if changesField(changes, "date", "priority", "reminders") {
+		result.AutoAdded, result.AutoRemoved = c.applyDefaultReminders(e)
+	}
+	result.Event = e.Clone()
+	result.Rescheduled = cloneReminders(result.Rescheduled)
+	result.Stale = cloneReminders(result.Stale)
+	result.AutoAdded = cloneReminders(result.AutoAdded)
+	result.AutoRemoved = cloneReminders(result.AutoRemoved)
+	return result, nil
+}
 
-	if err := c.calendarEvents[id].AddReminder(message, reminderAt); err != nil {
-		return fmt.Errorf("невозможно назначить напоминание событию: %w", err)
+func changesField(changes []events.FieldChange, fields ...string) bool {
+	for _, change := range changes {
+		if slices.Contains(fields, change.Field) {
+			return true
+		}
 	}
-	if err := c.calendarEvents[id].StartReminder(c.Notify); err != nil {
-		return fmt.Errorf("невозможно запустить добавленное напоминание: %w", err)
+	return false
+}
+
+// GetEvent возвращает копии всех событий календаря по их ID.
+func (c *Calendar) GetEvent() map[string]*events.Event {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	snapshot := make(map[string]*events.Event, len(c.calendarEvents))
+	for id, e := range c.calendarEvents {
+		snapshot[id] = e.Clone()
 	}
-	return nil
+	return snapshot
+}
 
+func cloneEvents(list []*events.Event) []*events.Event {
+	if list == nil {
+		return nil
+	}
+	clones := make([]*events.Event, len(list))
+	for i, e := range list {
+		clones[i] = e.Clone()
+	}
+	return clones
 }
 
-func (c *Calendar) calculateReminderTime(id, before string) (time.Time, error) {
-	duration, err := time.ParseDuration(before)
-	if err != nil {
-		return time.Time{}, ErrInvalidDuration
+func cloneReminders(list []*reminder.Reminder) []*reminder.Reminder {
+	if list == nil {
+		return nil
+	}
+	clones := make([]*reminder.Reminder, len(list))
+	for i, r := range list {
+		clones[i] = r.Clone()
 	}
+	return clones
+}
 
+func (c *Calendar) SetEventReminder(id, message, when string) (*reminder.Reminder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.idExists(id) {
+		return nil, fmt.Errorf("невозможно назначить напоминание событию: %w", ErrEventNotFound)
+	}
 	e := c.calendarEvents[id]
+	offset, at, err := parseReminderTime(e, when)
+	if err != nil {
+		return nil, fmt.Errorf("невозможно назначить напоминание событию: %w", err)
+	}
+	if err := validateReminderTime(e, at, c.clock.Now()); err != nil {
+		return nil, fmt.Errorf("невозможно назначить напоминание событию: %w", err)
+	}
+
+	var r *reminder.Reminder
+	if offset != nil {
+		r, err = e.AddReminderBefore(message, *offset)
+	} else {
+		r, err = e.AddReminder(message, at)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("невозможно назначить напоминание событию: %w", err)
+	}
+	if err := c.scheduleReminder(e, r); err != nil {
+		e.RemoveReminder(r.ID)
+		return nil, fmt.Errorf("невозможно запустить добавленное напоминание: %w", err)
+	}
+	return r.Clone(), nil
+}
+
+// parseReminderTime принимает либо интервал до начала события ("2h", "1d2h", "2w"),
+// либо абсолютные дату и время напоминания ("2025-10-10 20:00"). Для интервала
+// возвращается смещение, для абсолютного времени оно равно nil.
+func parseReminderTime(e *events.Event, when string) (*time.Duration, time.Time, error) {
+	if offset, err := timeutil.ParseDuration(when); err == nil {
+		return &offset, e.StartAt.Add(-offset), nil
+	}
+	if at, err := events.ValidateDate(when); err == nil {
+		return nil, at, nil
+	}
+	return nil, time.Time{}, ErrInvalidDuration
+}
+
+func validateReminderTime(e *events.Event, reminderAt, now time.Time) error {
 	eventStartAt := e.StartAt
-	reminderAt := eventStartAt.Add(-duration)
-	if eventStartAt.Before(time.Now()) {
-		return time.Time{}, ErrEventExpired
+	if eventStartAt.Before(now) {
+		return ErrEventExpired
 	}
 	if reminderAt.After(eventStartAt) {
-		return time.Time{}, ErrReminderTimeAfterEvent
+		return ErrReminderTimeAfterEvent
 	}
-	if reminderAt.Before(time.Now()) {
-		return time.Time{}, ErrReminderTimeBeforeNow
+	if reminderAt.Before(now) {
+		return ErrReminderTimeBeforeNow
 	}
-	return reminderAt, nil
+	return nil
 }
 
-func (c *Calendar) CancelEventReminder(id string) error {
+// CancelEventReminder останавливает и удаляет напоминание события и возвращает
+// его копию. Если reminderID не указан, удаляется единственное напоминание события.
+func (c *Calendar) CancelEventReminder(id, reminderID string) (*reminder.Reminder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if !c.idExists(id) {
-		return fmt.Errorf("невозможно удалить напоминание у события: %w", ErrEventNotFound)
+		return nil, fmt.Errorf("невозможно удалить напоминание у события: %w", ErrEventNotFound)
 	}
 	e := c.calendarEvents[id]
-	err := e.Reminder.Stop()
-	e.RemoveReminder()
-	return err
+	if reminderID == "" {
+		switch len(e.Reminders) {
+		case 0:
+			return nil, fmt.Errorf("невозможно удалить напоминание у события: %w", reminder.ErrNotExistReminder)
+		case 1:
+			reminderID = e.Reminders[0].ID
+		default:
+			return nil, fmt.Errorf("невозможно удалить напоминание у события: %w", ErrReminderNotSpecified)
+		}
+	}
+	r, err := e.RemoveReminder(reminderID)
+	if err != nil {
+		return nil, fmt.Errorf("невозможно удалить напоминание у события: %w", err)
+	}
+	c.dnd.Drop(r.ID)
+	if err := r.Cancel(c.scheduler); err != nil {
+		return nil, err
+	}
+	return r.Clone(), nil
+}
+
+// MissedReminder - напоминание, время которого прошло, пока приложение было закрыто.
+// Delivered означает, что оно попало в окно ожидания и все же было отправлено.
+type MissedReminder struct {
+	Event     *events.Event
+	Reminder  *reminder.Reminder
+	Delivered bool
+}
+
+func (c *Calendar) SetGraceWindow(d time.Duration) error {
+	if d < 0 {
+		return ErrInvalidDuration
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.graceWindow = d
+	return nil
 }
-func (c *Calendar) StartAllReminder() {
-	for _, event := range c.calendarEvents {
-		if event.Reminder != nil {
-			event.StartReminder(c.Notify)
+
+// Start запускает планировщик напоминаний и ставит в очередь все
+// неотправленные напоминания. Просроченные напоминания, опоздавшие не более
+// чем на окно ожидания, отправляются сразу, остальные помечаются пропущенными.
+// Планировщик останавливается при отмене ctx или вызове Stop.
+func (c *Calendar) Start(ctx context.Context) ([]MissedReminder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ctx, cancel := context.WithCancel(ctx)
+	if err := c.scheduler.Start(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	c.cancel = cancel
+	now := c.clock.Now()
+	var missed []MissedReminder
+	for _, event := range c.sortedEvents() {
+		if event.Cancelled {
+			continue
+		}
+		for _, r := range event.Reminders {
+			if !r.IsOverdue(now) {
+				continue
+			}
+			delivered := now.Sub(r.NextAt()) <= c.graceWindow
+			if delivered {
+				c.scheduler.Schedule(r.ID, now, func() { c.fire(event, r) })
+			} else {
+				r.Missed = true
+				c.scheduler.Cancel(r.ID)
+			}
+			missed = append(missed, MissedReminder{Event: event.Clone(), Reminder: r.Clone(), Delivered: delivered})
 		}
+		for _, r := range event.Reminders {
+			if r.IsPending() {
+				c.scheduleRepeat(event, r)
+			} else {
+				c.scheduleReminder(event, r)
+			}
+		}
+	}
+	c.scheduleDigest(now)
+	sort.SliceStable(missed, func(i, j int) bool {
+		return missed[i].Reminder.At.Before(missed[j].Reminder.At)
+	})
+	return missed, nil
+}
+
+// Stop останавливает планировщик, дожидается завершения уже начатой доставки
+// напоминаний, в том числе фоновой, и только после этого закрывает канал
+// Notification.
+func (c *Calendar) Stop() {
+	c.mu.Lock()
+	cancel := c.cancel
+	c.cancel = nil
+	c.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-c.scheduler.Done()
+	// задержанные напоминания не отмечены отправленными, поэтому после
+	// перезапуска они сработают снова, если не опоздали больше окна ожидания
+	if held := c.dnd.Release(); len(held) > 0 {
+		logger.Info(fmt.Sprintf("Задержанные напоминания отправятся при следующем запуске: %d", len(held)))
 	}
+	if err := c.notifiers.Close(); err != nil {
+		logger.Error(err.Error())
+	}
+	close(c.Notification)
 }
+
 func (c *Calendar) Notify(msg string) {
-	c.Notification <- msg
+	c.Notification <- Alert{Text: msg}
 }
 
 func (c *Calendar) idExists(id string) bool {
@@ -0,0 +1,137 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/reminder"
+	"testing"
+	"time"
+)
+
+func TestStartReportsMissedReminders(t *testing.T) {
+	c := NewCalendar(nil)
+	c.SetGraceWindow(30 * time.Minute)
+	added, _ := c.AddEvent("Планерка", time.Now().Add(time.Hour).Format("2006-01-02 15:04:05"), "low", 0)
+	e := c.calendarEvents[added.ID]
+	recent, _ := e.AddReminder("Недавнее", time.Now().Add(-10*time.Minute))
+	old, _ := e.AddReminder("Давнее", time.Now().Add(-2*time.Hour))
+	sent, _ := e.AddReminder("Отправленное", time.Now().Add(-3*time.Hour))
+	sent.Sent = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	missed, err := c.Start(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(missed) != 2 {
+		t.Fatalf("Expected two missed reminders, got %d", len(missed))
+	}
+	if missed[0].Reminder.ID != old.ID || missed[0].Delivered || !missed[0].Reminder.Missed {
+		t.Errorf("Expected old reminder to be marked missed, got %+v", missed[0])
+	}
+	if missed[1].Reminder.ID != recent.ID || !missed[1].Delivered {
+		t.Errorf("Expected recent reminder to be delivered, got %+v", missed[1])
+	}
+	select {
+	case alert := <-c.Notification:
+		msg := alert.Text
+		if msg != "Недавнее [ID: "+recent.ID+"]" {
+			t.Errorf("Expected recent reminder to be sent, got %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected recent reminder to be sent")
+	}
+}
+
+func TestSetEventReminderAcceptsAbsoluteTimeAndDays(t *testing.T) {
+	c := NewCalendar(nil)
+	start := time.Now().Add(72 * time.Hour).Truncate(time.Minute)
+	e, _ := c.AddEvent("Планерка", start.Format("2006-01-02 15:04"), "low", 0)
+
+	r, err := c.SetEventReminder(e.ID, "За день", "1d")
+	if err != nil || !r.At.Equal(start.Add(-24*time.Hour)) {
+		t.Errorf("Expected reminder one day before, got %v (%v)", r, err)
+	}
+	evening := start.Add(-12 * time.Hour).Format("2006-01-02 15:04")
+	r, err = c.SetEventReminder(e.ID, "Накануне", evening)
+	if err != nil || !r.At.Equal(start.Add(-12*time.Hour)) {
+		t.Errorf("Expected absolute reminder time, got %v (%v)", r, err)
+	}
+	if _, err := c.SetEventReminder(e.ID, "Поздно", start.Add(time.Hour).Format("2006-01-02 15:04")); !errors.Is(err, ErrReminderTimeAfterEvent) {
+		t.Errorf("Expected ErrReminderTimeAfterEvent, got %v", err)
+	}
+	if _, err := c.SetEventReminder(e.ID, "Рано", "1w"); !errors.Is(err, ErrReminderTimeBeforeNow) {
+		t.Errorf("Expected ErrReminderTimeBeforeNow, got %v", err)
+	}
+}
+
+func TestRemindersFollowRescheduledEvent(t *testing.T) {
+	c := NewCalendar(nil)
+	start := time.Now().Add(72 * time.Hour).Truncate(time.Minute)
+	e, _ := c.AddEvent("Планерка", start.Format("2006-01-02 15:04"), "low", 0)
+	relative, _ := c.SetEventReminder(e.ID, "За час", "1h")
+	absolute, _ := c.SetEventReminder(e.ID, "Накануне", start.Add(-24*time.Hour).Format("2006-01-02 15:04"))
+	longBefore, _ := c.SetEventReminder(e.ID, "За два дня", "2d")
+
+	newStart := start.Add(-48 * time.Hour).Format("2006-01-02 15:04")
+	result, err := c.PatchEvent(e.ID, events.EventPatch{Date: &newStart})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	moved, _ := result.Event.FindReminder(relative.ID)
+	if !moved.At.Equal(start.Add(-49 * time.Hour)) {
+		t.Errorf("Expected relative reminder to follow event, got %v", moved.At)
+	}
+	kept, _ := result.Event.FindReminder(absolute.ID)
+	if !kept.At.Equal(start.Add(-24 * time.Hour)) {
+		t.Errorf("Expected absolute reminder to stay, got %v", kept.At)
+	}
+	if len(result.Rescheduled) != 1 || result.Rescheduled[0].ID != relative.ID {
+		t.Errorf("Expected relative reminder to be rescheduled, got %v", result.Rescheduled)
+	}
+	if len(result.Stale) != 1 || result.Stale[0].ID != longBefore.ID {
+		t.Errorf("Expected two-day reminder to be stale, got %v", result.Stale)
+	}
+	if c.scheduler.IsScheduled(longBefore.ID) {
+		t.Error("Expected stale reminder not to be scheduled")
+	}
+}
+
+func TestReminderTemplateRenderedAtSendTime(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(2*time.Hour).Format(events.DateFormat), "low", 0)
+	r, _ := c.SetEventReminder(e.ID, "{{.Title}} начнется через {{.Until}} ({{.Priority}})", "1h")
+	byDefault, _ := c.SetEventReminder(e.ID, "", "30m")
+	if _, err := c.SetEventReminder(e.ID, "{{.Place}}", "20m"); !errors.Is(err, reminder.ErrInvalidTemplate) {
+		t.Errorf("Expected ErrInvalidTemplate, got %v", err)
+	}
+	c.Start(context.Background())
+
+	title, priority := "Совещание", events.PriorityHigh
+	c.PatchEvent(e.ID, events.EventPatch{Title: &title, Priority: &priority})
+	clock.Advance(time.Hour)
+	expectNotification(t, c, "Совещание начнется через 1h (high) [ID: "+r.ID+"]")
+	clock.Advance(30 * time.Minute)
+	expectNotification(t, c, "Совещание - через 30m (high) [ID: "+byDefault.ID+"]")
+}
+
+func TestCancelEventReminderReturnsRemoved(t *testing.T) {
+	c := NewCalendar(nil)
+	e, _ := c.AddEvent("Планерка", time.Now().Add(2*time.Hour).Format(events.DateFormat), "low", 0)
+	first, _ := c.SetEventReminder(e.ID, "За час", "1h")
+	c.SetEventReminder(e.ID, "За полчаса", "30m")
+
+	if _, err := c.CancelEventReminder(e.ID, ""); !errors.Is(err, ErrReminderNotSpecified) {
+		t.Errorf("Expected ErrReminderNotSpecified, got %v", err)
+	}
+	removed, err := c.CancelEventReminder(e.ID, first.ID)
+	if err != nil || removed.ID != first.ID {
+		t.Fatalf("Expected removed reminder %s, got %v (%v)", first.ID, removed, err)
+	}
+	last := c.GetEvent()[e.ID].Reminders[0]
+	if removed, err := c.CancelEventReminder(e.ID, ""); err != nil || removed.ID != last.ID {
+		t.Errorf("Expected the only reminder %s to be removed, got %v (%v)", last.ID, removed, err)
+	}
+}
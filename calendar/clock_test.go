@@ -0,0 +1,162 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/timeutil"
+	"testing"
+	"time"
+)
+
+var clockStart = time.Date(2025, 10, 11, 9, 0, 0, 0, time.Local)
+
+func newFakeCalendar(t *testing.T) (*Calendar, *timeutil.FakeClock) {
+	t.Helper()
+	clock := timeutil.NewFakeClock(clockStart)
+	c := NewCalendarWithClock(nil, clock)
+	t.Cleanup(func() {
+		go func() {
+			for range c.Notification {
+			}
+		}()
+		c.Stop()
+	})
+	return c, clock
+}
+
+func expectNotification(t *testing.T, c *Calendar, expected string) {
+	t.Helper()
+	select {
+	case alert := <-c.Notification:
+		msg := alert.Text
+		if msg != expected {
+			t.Errorf("Expected %q, got %q", expected, msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected %q to be sent", expected)
+	}
+}
+
+func expectNoNotification(t *testing.T, c *Calendar) {
+	t.Helper()
+	select {
+	case alert := <-c.Notification:
+		msg := alert.Text
+		t.Errorf("Expected no notification, got %q", msg)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestReminderFiresWhenClockReachesIt(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(2*time.Hour).Format(events.DateFormat), "low", 0)
+	r, err := c.SetEventReminder(e.ID, "Скоро планерка", "1h")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	c.Start(context.Background())
+
+	clock.Advance(59 * time.Minute)
+	expectNoNotification(t, c)
+	clock.Advance(time.Minute)
+	expectNotification(t, c, "Скоро планерка [ID: "+r.ID+"]")
+
+	if pending := c.PendingReminders(); len(pending) != 1 || !pending[0].Reminder.Sent {
+		t.Errorf("Expected fired reminder to be pending, got %v", pending)
+	}
+}
+
+func TestReminderExpiry(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(3*time.Hour).Format(events.DateFormat), "low", 0)
+	if _, err := c.SetEventReminder(e.ID, "Раньше времени", clockStart.Add(-time.Minute).Format(events.DateFormat)); !errors.Is(err, ErrReminderTimeBeforeNow) {
+		t.Errorf("Expected ErrReminderTimeBeforeNow, got %v", err)
+	}
+	r, _ := c.SetEventReminder(e.ID, "За два часа", "2h")
+
+	clock.Advance(90 * time.Minute)
+	missed, _ := c.Start(context.Background())
+	if len(missed) != 1 || missed[0].Reminder.ID != r.ID || !missed[0].Delivered {
+		t.Fatalf("Expected reminder inside grace window to be delivered, got %v", missed)
+	}
+	expectNotification(t, c, "За два часа [ID: "+r.ID+"]")
+
+	clock.Set(clockStart.Add(3*time.Hour + time.Minute))
+	if _, err := c.SetEventReminder(e.ID, "Поздно", "10m"); !errors.Is(err, ErrEventExpired) {
+		t.Errorf("Expected ErrEventExpired, got %v", err)
+	}
+}
+
+func TestMissedReminderOutsideGraceWindow(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	c.SetGraceWindow(30 * time.Minute)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(3*time.Hour).Format(events.DateFormat), "low", 0)
+	r, _ := c.SetEventReminder(e.ID, "За два часа", "2h")
+
+	clock.Advance(2 * time.Hour)
+	missed, _ := c.Start(context.Background())
+	if len(missed) != 1 || missed[0].Reminder.ID != r.ID || missed[0].Delivered || !missed[0].Reminder.Missed {
+		t.Fatalf("Expected reminder to be marked missed, got %v", missed)
+	}
+	clock.Advance(time.Hour)
+	expectNoNotification(t, c)
+}
+
+func TestReminderFollowsRescheduledEventOnClock(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(2*time.Hour).Format(events.DateFormat), "low", 0)
+	r, _ := c.SetEventReminder(e.ID, "Скоро планерка", "1h")
+	c.Start(context.Background())
+
+	later := clockStart.Add(26 * time.Hour).Format(events.DateFormat)
+	if _, err := c.PatchEvent(e.ID, events.EventPatch{Date: &later}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	clock.Advance(time.Hour)
+	expectNoNotification(t, c)
+	clock.Advance(24 * time.Hour)
+	expectNotification(t, c, "Скоро планерка [ID: "+r.ID+"]")
+}
+
+func TestHighPriorityReminderRepeatsUntilAcknowledged(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	c.SetRepeatInterval(10 * time.Minute)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(2*time.Hour).Format(events.DateFormat), "high", 0)
+	r, _ := c.SetEventReminder(e.ID, "Скоро планерка", "1h")
+	c.Start(context.Background())
+	expected := "Скоро планерка [ID: " + r.ID + "]"
+
+	clock.Advance(time.Hour)
+	expectNotification(t, c, expected)
+	clock.Advance(10 * time.Minute)
+	expectNotification(t, c, expected)
+
+	if _, err := c.AcknowledgeReminder(r.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	clock.Advance(10 * time.Minute)
+	expectNoNotification(t, c)
+}
+
+func TestSnoozedReminderFiresAgain(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(2*time.Hour).Format(events.DateFormat), "low", 0)
+	r, _ := c.SetEventReminder(e.ID, "Скоро планерка", "1h")
+	c.Start(context.Background())
+	expected := "Скоро планерка [ID: " + r.ID + "]"
+
+	clock.Advance(time.Hour)
+	expectNotification(t, c, expected)
+	snoozed, err := c.SnoozeReminder(r.ID, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !snoozed.Reminder.NextAt().Equal(clockStart.Add(75 * time.Minute)) {
+		t.Errorf("Expected reminder snoozed by fake clock, got %v", snoozed.Reminder.NextAt())
+	}
+	clock.Advance(14 * time.Minute)
+	expectNoNotification(t, c)
+	clock.Advance(time.Minute)
+	expectNotification(t, c, expected)
+}
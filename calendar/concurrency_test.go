@@ -0,0 +1,72 @@
+package calendar
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentEditsWhileRemindersFire(t *testing.T) {
+	c := NewCalendar(nil)
+	c.SetRepeatInterval(5 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Start(ctx)
+
+	go func() {
+		for range c.Notification {
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				e, err := c.AddEvent("Планерка", time.Now().Add(time.Hour).Format("2006-01-02 15:04:05"), "high", 0)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+					return
+				}
+				c.SetEventReminder(e.ID, "Скоро", time.Now().Add(time.Millisecond).Format("2006-01-02 15:04:05"))
+				c.Query(Query{})
+				c.PendingReminders()
+				c.Search("планерка")
+				c.ConflictsInRange(time.Time{}, time.Time{})
+				if j%2 == 0 {
+					c.DeleteEvent(e.ID)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	c.Stop()
+}
+
+func TestStopClosesNotificationAfterScheduler(t *testing.T) {
+	c := NewCalendar(nil)
+	ctx := context.Background()
+	c.Start(ctx)
+	e, _ := c.AddEvent("Планерка", time.Now().Add(time.Hour).Format("2006-01-02 15:04:05"), "low", 0)
+	live := c.calendarEvents[e.ID]
+	c.mu.Lock()
+	r, _ := live.AddReminder("Скоро", time.Now())
+	c.mu.Unlock()
+	c.scheduler.Schedule(r.ID, time.Now(), func() { c.fire(live, r) })
+
+	done := make(chan struct{})
+	go func() {
+		for range c.Notification {
+		}
+		close(done)
+	}()
+	c.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Notification to be closed after Stop")
+	}
+	c.Stop()
+}
@@ -0,0 +1,160 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/events"
+	"sort"
+	"time"
+)
+
+var (
+	ErrEventConflict         = errors.New("событие пересекается с другими событиями")
+	ErrInvalidConflictPolicy = errors.New("некорректная политика пересечений")
+)
+
+type ConflictPolicy string
+
+const (
+	ConflictIgnore ConflictPolicy = "ignore"
+	ConflictWarn   ConflictPolicy = "warn"
+	ConflictBlock  ConflictPolicy = "block"
+)
+
+func (p ConflictPolicy) Validate() error {
+	switch p {
+	case ConflictIgnore, ConflictWarn, ConflictBlock:
+		return nil
+	default:
+		return ErrInvalidConflictPolicy
+	}
+}
+
+// ConflictError возвращается при политике ConflictBlock и содержит
+// события, с которыми пересекается добавляемое или изменяемое событие.
+type ConflictError struct {
+	Conflicts []*events.Event
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s (%d)", ErrEventConflict.Error(), len(e.Conflicts))
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrEventConflict
+}
+
+// Conflict - пара пересекающихся событий, First начинается не позже Second.
+type Conflict struct {
+	First  *events.Event
+	Second *events.Event
+}
+
+func (c *Calendar) SetConflictPolicy(policy ConflictPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conflictPolicy = policy
+	return nil
+}
+
+func (c *Calendar) ConflictPolicy() ConflictPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.conflictPolicy
+}
+
+func (c *Calendar) SetDefaultDuration(d time.Duration) error {
+	if err := events.ValidateDuration(d); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaultDuration = d
+	return nil
+}
+
+func (c *Calendar) DefaultDuration() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.defaultDuration
+}
+
+func (c *Calendar) EventConflicts(id string) ([]*events.Event, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.idExists(id) {
+		return nil, fmt.Errorf("невозможно проверить пересечения: %w", ErrEventNotFound)
+	}
+	return cloneEvents(c.findConflicts(c.calendarEvents[id])), nil
+}
+
+// ConflictsInRange возвращает все пары пересекающихся событий, чье общее время
+// попадает в интервал [from, to). Нулевые границы означают отсутствие ограничения.
+func (c *Calendar) ConflictsInRange(from, to time.Time) []Conflict {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	sorted := c.activeEvents()
+	var conflicts []Conflict
+	for i, first := range sorted {
+		for _, second := range sorted[i+1:] {
+			if !second.StartAt.Before(first.EndAt(c.defaultDuration)) {
+				break
+			}
+			overlapStart := second.StartAt
+			overlapEnd := minTime(first.EndAt(c.defaultDuration), second.EndAt(c.defaultDuration))
+			if !from.IsZero() && !overlapEnd.After(from) {
+				continue
+			}
+			if !to.IsZero() && !overlapStart.Before(to) {
+				continue
+			}
+			conflicts = append(conflicts, Conflict{First: first.Clone(), Second: second.Clone()})
+		}
+	}
+	return conflicts
+}
+
+func (c *Calendar) findConflicts(e *events.Event) []*events.Event {
+	var conflicts []*events.Event
+	for _, other := range c.activeEvents() {
+		if other.ID != e.ID && e.Overlaps(other, c.defaultDuration) {
+			conflicts = append(conflicts, other)
+		}
+	}
+	return conflicts
+}
+
+func (c *Calendar) sortedEvents() []*events.Event {
+	sorted := make([]*events.Event, 0, len(c.calendarEvents))
+	for _, e := range c.calendarEvents {
+		sorted = append(sorted, e)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].StartAt.Equal(sorted[j].StartAt) {
+			return sorted[i].ID < sorted[j].ID
+		}
+		return sorted[i].StartAt.Before(sorted[j].StartAt)
+	})
+	return sorted
+}
+
+// activeEvents возвращает неотмененные события в порядке начала.
+func (c *Calendar) activeEvents() []*events.Event {
+	var active []*events.Event
+	for _, e := range c.sortedEvents() {
+		if !e.Cancelled {
+			active = append(active, e)
+		}
+	}
+	return active
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,41 @@
+package calendar
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConflictPolicyBlock(t *testing.T) {
+	c := NewCalendar(nil)
+	if err := c.SetConflictPolicy(ConflictBlock); err != nil {
+		t.Fatalf("Expected no error for policy, got %v", err)
+	}
+	first, err := c.AddEvent("Планерка", "2030-10-11 15:00", "low", time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error for first event, got %v", err)
+	}
+	_, err = c.AddEvent("Созвон", "2030-10-11 15:30", "low", 0)
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) || conflictErr.Conflicts[0].ID != first.ID {
+		t.Errorf("Expected conflict with first event, got %v", err)
+	}
+	if _, err := c.AddEvent("Обед", "2030-10-11 16:00", "low", 0); err != nil {
+		t.Errorf("Expected no error for adjacent event, got %v", err)
+	}
+}
+
+func TestConflictsInRange(t *testing.T) {
+	c := NewCalendar(nil)
+	c.AddEvent("Планерка", "2030-10-11 15:00", "low", 2*time.Hour)
+	c.AddEvent("Созвон", "2030-10-11 16:00", "low", 0)
+	c.AddEvent("Ужин", "2030-10-12 19:00", "low", 0)
+
+	if conflicts := c.ConflictsInRange(time.Time{}, time.Time{}); len(conflicts) != 1 {
+		t.Errorf("Expected one conflict, got %d", len(conflicts))
+	}
+	from := time.Date(2030, 10, 12, 0, 0, 0, 0, time.Local)
+	if conflicts := c.ConflictsInRange(from, time.Time{}); len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts after %v, got %d", from, len(conflicts))
+	}
+}
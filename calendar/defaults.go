@@ -0,0 +1,75 @@
+package calendar
+
+import (
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/reminder"
+	"slices"
+	"time"
+)
+
+// SetDefaultReminders задает политику напоминаний по умолчанию: для каждого
+// приоритета - интервалы до начала события.
+func (c *Calendar) SetDefaultReminders(policy map[events.Priority][]time.Duration) error {
+	for priority, offsets := range policy {
+		if err := priority.Validate(); err != nil {
+			return err
+		}
+		defaults := events.ReminderDefaults{Custom: true, Offsets: offsets}
+		if err := defaults.Validate(); err != nil {
+			return err
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaultReminders = policy
+	return nil
+}
+
+func (c *Calendar) defaultOffsets(e *events.Event) []time.Duration {
+	if e.ReminderDefaults != nil {
+		return e.ReminderDefaults.Offsets
+	}
+	return c.defaultReminders[e.Priority]
+}
+
+// applyDefaultReminders приводит автоматические напоминания события в соответствие
+// с политикой: лишние удаляет, недостающие создает. Интервалы, уже покрытые
+// напоминаниями пользователя, и интервалы, чье время прошло, пропускаются.
+func (c *Calendar) applyDefaultReminders(e *events.Event) (added, removed []*reminder.Reminder) {
+	offsets := c.defaultOffsets(e)
+	for _, r := range slices.Clone(e.Reminders) {
+		if r.Auto && !slices.Contains(offsets, r.Offset) {
+			e.RemoveReminder(r.ID)
+			c.scheduler.Cancel(r.ID)
+			c.dnd.Drop(r.ID)
+			removed = append(removed, r)
+		}
+	}
+	now := c.clock.Now()
+	for _, offset := range offsets {
+		at := e.StartAt.Add(-offset)
+		if at.Before(now) || hasReminderAt(e, offset, at) {
+			continue
+		}
+		r, err := e.AddReminderBefore("", offset)
+		if err != nil {
+			continue
+		}
+		r.Auto = true
+		if err := c.scheduleReminder(e, r); err != nil {
+			e.RemoveReminder(r.ID)
+			continue
+		}
+		added = append(added, r)
+	}
+	return added, removed
+}
+
+func hasReminderAt(e *events.Event, offset time.Duration, at time.Time) bool {
+	for _, r := range e.Reminders {
+		if (!r.Absolute && r.Offset == offset) || r.At.Equal(at) {
+			return true
+		}
+	}
+	return false
+}
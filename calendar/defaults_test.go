@@ -0,0 +1,38 @@
+package calendar
+
+import (
+	"github.com/elizavetanr/myDays/events"
+	"testing"
+	"time"
+)
+
+func TestDefaultRemindersFollowPriority(t *testing.T) {
+	c := NewCalendar(nil)
+	c.SetDefaultReminders(map[events.Priority][]time.Duration{
+		events.PriorityHigh:   {24 * time.Hour, time.Hour},
+		events.PriorityMedium: {30 * time.Minute},
+	})
+	start := time.Now().Add(72 * time.Hour).Format("2006-01-02 15:04")
+	e, _ := c.AddEvent("Планерка", start, events.PriorityMedium, 0)
+	if len(e.Reminders) != 1 || !e.Reminders[0].Auto || e.Reminders[0].Offset != 30*time.Minute {
+		t.Fatalf("Expected one default reminder for medium priority, got %v", e.Reminders)
+	}
+
+	manual, _ := c.SetEventReminder(e.ID, "Свой", "1h")
+	high := events.PriorityHigh
+	result, err := c.PatchEvent(e.ID, events.EventPatch{Priority: &high})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.AutoRemoved) != 1 || len(result.AutoAdded) != 1 || result.AutoAdded[0].Offset != 24*time.Hour {
+		t.Errorf("Expected medium default replaced by one-day reminder, got +%v -%v", result.AutoAdded, result.AutoRemoved)
+	}
+	if len(result.Event.Reminders) != 2 || result.Event.Reminders[0].ID != manual.ID {
+		t.Errorf("Expected manual one-hour reminder not to be duplicated, got %v", result.Event.Reminders)
+	}
+
+	result, _ = c.PatchEvent(e.ID, events.EventPatch{ReminderDefaults: &events.ReminderDefaults{Custom: true}})
+	if len(result.AutoRemoved) != 1 || len(result.Event.Reminders) != 1 {
+		t.Errorf("Expected per-event override to remove defaults, got %v", result.Event.Reminders)
+	}
+}
@@ -0,0 +1,123 @@
+package calendar
+
+import (
+	"fmt"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/timeutil"
+	"sort"
+	"strings"
+	"time"
+)
+
+const digestJobID = "digest"
+
+// Digest - сводка событий на день. Overdue - уже начавшиеся события,
+// у которых остались неподтвержденные напоминания.
+type Digest struct {
+	Day      time.Time
+	Today    []*events.Event
+	Tomorrow []*events.Event
+	Overdue  []*events.Event
+}
+
+// SetDigest включает ежедневную сводку в момент at от начала суток.
+// Отрицательное at отключает сводку.
+func (c *Calendar) SetDigest(at time.Duration, includeTomorrow bool) error {
+	if at >= 24*time.Hour {
+		return ErrInvalidDuration
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.digestAt = at
+	c.digestTomorrow = includeTomorrow
+	return nil
+}
+
+func (c *Calendar) Digest(now time.Time, includeTomorrow bool) Digest {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	d := Digest{Day: timeutil.StartOfDay(now)}
+	today, tomorrow := d.Day, d.Day.AddDate(0, 0, 1)
+	for _, e := range c.sortedEvents() {
+		switch {
+		case !e.StartAt.Before(today) && e.StartAt.Before(tomorrow):
+			d.Today = append(d.Today, e.Clone())
+		case includeTomorrow && !e.StartAt.Before(tomorrow) && e.StartAt.Before(tomorrow.AddDate(0, 0, 1)):
+			d.Tomorrow = append(d.Tomorrow, e.Clone())
+		}
+		if e.StartAt.Before(now) && !e.Cancelled && hasPendingReminder(e) {
+			d.Overdue = append(d.Overdue, e.Clone())
+		}
+	}
+	sortByTimeAndPriority(d.Today)
+	sortByTimeAndPriority(d.Tomorrow)
+	return d
+}
+
+func (d Digest) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Сводка на %s", d.Day.Format("2006-01-02"))
+	writeDigestDay(&b, "Сегодня", d.Today)
+	if d.Tomorrow != nil {
+		writeDigestDay(&b, "Завтра", d.Tomorrow)
+	}
+	if len(d.Overdue) > 0 {
+		b.WriteString("\nПросрочено:")
+		for _, e := range d.Overdue {
+			fmt.Fprintf(&b, "\n  ! %s %s (%s)", e.StartAt.Format(events.DateFormat), e.Title, e.Priority)
+		}
+	}
+	return b.String()
+}
+
+func writeDigestDay(b *strings.Builder, title string, list []*events.Event) {
+	if len(list) == 0 {
+		fmt.Fprintf(b, "\n%s: событий нет", title)
+		return
+	}
+	fmt.Fprintf(b, "\n%s:", title)
+	for _, e := range list {
+		line := fmt.Sprintf("\n  %s %s (%s)", e.StartAt.Format("15:04"), e.Title, e.Priority)
+		if e.Cancelled {
+			line = fmt.Sprintf("\n  x %s %s (%s) - отменено", e.StartAt.Format("15:04"), e.Title, e.Priority)
+		}
+		b.WriteString(line)
+	}
+}
+
+func sortByTimeAndPriority(list []*events.Event) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if !list[i].StartAt.Equal(list[j].StartAt) {
+			return list[i].StartAt.Before(list[j].StartAt)
+		}
+		return list[i].Priority.Rank() > list[j].Priority.Rank()
+	})
+}
+
+func hasPendingReminder(e *events.Event) bool {
+	for _, r := range e.Reminders {
+		if r.IsPending() {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleDigest планирует отправку ближайшей ежедневной сводки.
+// Вызывается под блокировкой календаря.
+func (c *Calendar) scheduleDigest(now time.Time) {
+	if c.digestAt < 0 {
+		return
+	}
+	next := timeutil.AtClock(now, c.digestAt)
+	if !next.After(now) {
+		next = timeutil.AtClock(timeutil.StartOfDay(now).AddDate(0, 0, 1), c.digestAt)
+	}
+	includeTomorrow := c.digestTomorrow
+	c.scheduler.Schedule(digestJobID, next, func() {
+		c.Notify(c.Digest(c.clock.Now(), includeTomorrow).String())
+		c.mu.RLock()
+		c.scheduleDigest(c.clock.Now())
+		c.mu.RUnlock()
+	})
+}
@@ -0,0 +1,79 @@
+package calendar
+
+import (
+	"context"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/reminder"
+	"github.com/elizavetanr/myDays/timeutil"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestDigestSortsAndMarksEvents(t *testing.T) {
+	c := NewCalendar(nil)
+	now := time.Date(2025, 10, 11, 12, 0, 0, 0, time.Local)
+	add := func(id, title string, at time.Time, priority events.Priority) *events.Event {
+		e := &events.Event{ID: id, Title: title, StartAt: at, Priority: priority}
+		c.calendarEvents[id] = e
+		return e
+	}
+	add("1", "Обед", now.Add(2*time.Hour), events.PriorityLow)
+	add("2", "Встреча", now.Add(2*time.Hour), events.PriorityHigh)
+	overdue := add("3", "Звонок", now.Add(-time.Hour), events.PriorityMedium)
+	overdue.Reminders = []*reminder.Reminder{{ID: "r", At: now.Add(-2 * time.Hour), Sent: true}}
+	add("4", "Отмененное", now.Add(3*time.Hour), events.PriorityMedium).Cancelled = true
+	add("5", "Завтрак", now.Add(20*time.Hour), events.PriorityLow)
+
+	d := c.Digest(now, false)
+	if len(d.Today) != 4 || d.Today[1].ID != "2" || d.Today[2].ID != "1" {
+		t.Errorf("Expected today's events sorted by time then priority, got %v", d.Today)
+	}
+	if len(d.Overdue) != 1 || d.Overdue[0].ID != "3" {
+		t.Errorf("Expected one overdue event, got %v", d.Overdue)
+	}
+	if d.Tomorrow != nil {
+		t.Errorf("Expected no tomorrow section, got %v", d.Tomorrow)
+	}
+	if !strings.Contains(d.String(), "Отмененное (medium) - отменено") {
+		t.Errorf("Expected cancelled event to be marked, got %q", d.String())
+	}
+
+	d = c.Digest(now, true)
+	if len(d.Tomorrow) != 1 || d.Tomorrow[0].ID != "5" {
+		t.Errorf("Expected one event tomorrow, got %v", d.Tomorrow)
+	}
+}
+
+func TestDigestOnDaylightSavingDay(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// 30 марта 2025 года в Берлине часы переводятся с 02:00 на 03:00
+	start := time.Date(2025, 3, 29, 12, 0, 0, 0, berlin)
+	clock := timeutil.NewFakeClock(start)
+	c := NewCalendarWithClock(nil, clock)
+	t.Cleanup(func() {
+		go func() {
+			for range c.Notification {
+			}
+		}()
+		c.Stop()
+	})
+	c.SetDigest(8*time.Hour, false)
+	c.Start(context.Background())
+
+	clock.Set(time.Date(2025, 3, 30, 7, 59, 0, 0, berlin))
+	expectNoNotification(t, c)
+	clock.Set(time.Date(2025, 3, 30, 8, 0, 0, 0, berlin))
+	select {
+	case alert := <-c.Notification:
+		if !strings.HasPrefix(alert.Text, "Сводка на 2025-03-30") {
+			t.Errorf("Expected digest for March 30, got %q", alert.Text)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected digest at 08:00 by the wall clock")
+	}
+}
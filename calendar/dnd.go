@@ -0,0 +1,68 @@
+package calendar
+
+import (
+	"fmt"
+	"github.com/elizavetanr/myDays/reminder"
+	"time"
+)
+
+const flushJobID = "dnd-flush"
+
+func (c *Calendar) DoNotDisturb() *reminder.DoNotDisturb {
+	return c.dnd
+}
+
+// EnableDoNotDisturb включает режим "не беспокоить" на d и возвращает время его окончания.
+func (c *Calendar) EnableDoNotDisturb(d time.Duration) (time.Time, error) {
+	if d <= 0 {
+		return time.Time{}, ErrInvalidDuration
+	}
+	until := c.clock.Now().Add(d)
+	c.dnd.Enable(until)
+	c.scheduler.Schedule(flushJobID, until, c.flushHeld)
+	return until, nil
+}
+
+// DisableDoNotDisturb выключает режим "не беспокоить" и отправляет задержанные
+// напоминания, если не действуют тихие часы.
+func (c *Calendar) DisableDoNotDisturb() {
+	c.dnd.Disable()
+	c.scheduler.Schedule(flushJobID, c.clock.Now(), c.flushHeld)
+}
+
+func (c *Calendar) HeldNotifications() []reminder.Notification {
+	return c.dnd.Held()
+}
+
+// deliver отправляет напоминание всеми настроенными способами
+// или задерживает его до окончания тишины.
+func (c *Calendar) deliver(n reminder.Notification) {
+	if end, held := c.dnd.Hold(n, c.clock.Now()); held {
+		c.scheduler.Schedule(flushJobID, end, c.flushHeld)
+		return
+	}
+	c.send(n)
+}
+
+// send отмечает напоминание отправленным и доставляет его, если оно
+// все еще актуально.
+func (c *Calendar) send(n reminder.Notification) {
+	if c.markSent(n.ReminderID) {
+		c.dispatch(n)
+	}
+}
+
+func (c *Calendar) flushHeld() {
+	if end, active := c.dnd.ActiveUntil(c.clock.Now()); active {
+		c.scheduler.Schedule(flushJobID, end, c.flushHeld)
+		return
+	}
+	held := c.dnd.Release()
+	if len(held) == 0 {
+		return
+	}
+	c.Notify(fmt.Sprintf("Напоминания, задержанные режимом \"не беспокоить\": %d", len(held)))
+	for _, n := range held {
+		c.send(n)
+	}
+}
@@ -0,0 +1,86 @@
+package calendar
+
+import (
+	"context"
+	"github.com/elizavetanr/myDays/events"
+	"testing"
+	"time"
+)
+
+// waitHeld ждет, пока в очереди тишины окажется n напоминаний.
+func waitHeld(t *testing.T, c *Calendar, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for len(c.HeldNotifications()) != n && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if held := c.HeldNotifications(); len(held) != n {
+		t.Fatalf("Expected %d held notifications, got %v", n, held)
+	}
+}
+
+func TestHeldReminderIsNotPendingUntilDelivered(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(3*time.Hour).Format(events.DateFormat), "low", 0)
+	r, _ := c.SetEventReminder(e.ID, "Скоро планерка", "2h")
+	c.Start(context.Background())
+	c.EnableDoNotDisturb(2 * time.Hour)
+
+	clock.Advance(time.Hour)
+	waitHeld(t, c, 1)
+	if pending := c.PendingReminders(); len(pending) != 0 {
+		t.Errorf("Expected held reminder not to be pending, got %v", pending)
+	}
+
+	clock.Advance(time.Hour)
+	expectNotification(t, c, "Напоминания, задержанные режимом \"не беспокоить\": 1")
+	expectNotification(t, c, "Скоро планерка [ID: "+r.ID+"]")
+	if pending := c.PendingReminders(); len(pending) != 1 || pending[0].Reminder.ID != r.ID {
+		t.Errorf("Expected delivered reminder to be pending, got %v", pending)
+	}
+}
+
+func TestHeldRepeatsAreDedupedAndDroppedOnAck(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	c.SetRepeatInterval(10 * time.Minute)
+	c.DoNotDisturb().SetBreakthrough(false)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(2*time.Hour).Format(events.DateFormat), "high", 0)
+	r, _ := c.SetEventReminder(e.ID, "Скоро планерка", "1h")
+	c.Start(context.Background())
+
+	clock.Advance(time.Hour)
+	expectNotification(t, c, "Скоро планерка [ID: "+r.ID+"]")
+	c.EnableDoNotDisturb(time.Hour)
+	for range 3 {
+		clock.Advance(10 * time.Minute)
+		waitHeld(t, c, 1)
+	}
+
+	if _, err := c.AcknowledgeReminder(r.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if held := c.HeldNotifications(); len(held) != 0 {
+		t.Errorf("Expected acknowledged reminder to leave the queue, got %v", held)
+	}
+	c.DisableDoNotDisturb()
+	expectNoNotification(t, c)
+}
+
+func TestHeldReminderStaysUnsentAfterStop(t *testing.T) {
+	c, clock := newFakeCalendar(t)
+	e, _ := c.AddEvent("Планерка", clockStart.Add(2*time.Hour).Format(events.DateFormat), "low", 0)
+	c.SetEventReminder(e.ID, "Скоро планерка", "1h")
+	c.Start(context.Background())
+	c.EnableDoNotDisturb(2 * time.Hour)
+
+	clock.Advance(time.Hour)
+	waitHeld(t, c, 1)
+	go func() {
+		for range c.Notification {
+		}
+	}()
+	c.Stop()
+	if r := c.GetEvent()[e.ID].Reminders[0]; r.Sent {
+		t.Error("Expected held reminder to be sent again after restart")
+	}
+}
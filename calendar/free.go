@@ -0,0 +1,138 @@
+package calendar
+
+import (
+	"errors"
+	"github.com/elizavetanr/myDays/timeutil"
+	"sort"
+	"time"
+)
+
+var (
+	ErrInvalidWorkingHours = errors.New("некорректный формат рабочих часов")
+	ErrInvalidRange        = errors.New("некорректный интервал дат")
+)
+
+// WorkingHours задает рабочее время как смещения от начала суток.
+type WorkingHours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+var DefaultWorkingHours = WorkingHours{Start: 9 * time.Hour, End: 18 * time.Hour}
+
+// ParseWorkingHours разбирает строку вида "09:00-18:00".
+func ParseWorkingHours(s string) (WorkingHours, error) {
+	start, end, err := timeutil.ParseClockRange(s)
+	if err != nil {
+		return WorkingHours{}, ErrInvalidWorkingHours
+	}
+	h := WorkingHours{Start: start, End: end}
+	if err := h.Validate(); err != nil {
+		return WorkingHours{}, err
+	}
+	return h, nil
+}
+
+func (h WorkingHours) Validate() error {
+	if h.Start < 0 || h.End > 24*time.Hour || h.Start >= h.End {
+		return ErrInvalidWorkingHours
+	}
+	return nil
+}
+
+func (h WorkingHours) String() string {
+	return timeutil.FormatClock(h.Start) + "-" + timeutil.FormatClock(h.End)
+}
+
+type Slot struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (s Slot) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+func (c *Calendar) SetWorkingHours(h WorkingHours) error {
+	if err := h.Validate(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.workingHours = h
+	return nil
+}
+
+func (c *Calendar) WorkingHours() WorkingHours {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.workingHours
+}
+
+func (c *Calendar) FreeSlots(from, to time.Time, duration time.Duration, hours WorkingHours) ([]Slot, error) {
+	return FreeSlots([]*Calendar{c}, from, to, duration, hours)
+}
+
+// FreeSlots ищет в интервале [from, to) промежутки рабочего времени не короче
+// duration, свободные во всех переданных календарях. Слоты упорядочены
+// по времени начала, то есть по ближайшей доступности.
+func FreeSlots(calendars []*Calendar, from, to time.Time, duration time.Duration, hours WorkingHours) ([]Slot, error) {
+	if !from.Before(to) {
+		return nil, ErrInvalidRange
+	}
+	if duration <= 0 {
+		return nil, ErrInvalidDuration
+	}
+	if err := hours.Validate(); err != nil {
+		return nil, err
+	}
+
+	busy := busyIntervals(calendars, from, to)
+	var slots []Slot
+	for day := timeutil.StartOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		windowStart := maxTime(timeutil.AtClock(day, hours.Start), from)
+		windowEnd := minTime(timeutil.AtClock(day, hours.End), to)
+		cursor := windowStart
+		for _, b := range busy {
+			if !b.End.After(cursor) {
+				continue
+			}
+			if !b.Start.Before(windowEnd) {
+				break
+			}
+			if b.Start.Sub(cursor) >= duration {
+				slots = append(slots, Slot{Start: cursor, End: b.Start})
+			}
+			cursor = b.End
+		}
+		if windowEnd.Sub(cursor) >= duration {
+			slots = append(slots, Slot{Start: cursor, End: windowEnd})
+		}
+	}
+	return slots, nil
+}
+
+func busyIntervals(calendars []*Calendar, from, to time.Time) []Slot {
+	var busy []Slot
+	for _, c := range calendars {
+		c.mu.RLock()
+		for _, e := range c.activeEvents() {
+			end := e.EndAt(c.defaultDuration)
+			if end.After(from) && e.StartAt.Before(to) {
+				busy = append(busy, Slot{Start: e.StartAt, End: end})
+			}
+		}
+		c.mu.RUnlock()
+	}
+	sort.Slice(busy, func(i, j int) bool {
+		return busy[i].Start.Before(busy[j].Start)
+	})
+	return busy
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
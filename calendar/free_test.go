@@ -0,0 +1,61 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestFreeSlotsAcrossCalendars(t *testing.T) {
+	mine := NewCalendar(nil)
+	mine.AddEvent("Планерка", "2030-10-11 10:00", "low", time.Hour)
+	theirs := NewCalendar(nil)
+	theirs.AddEvent("Созвон", "2030-10-11 13:00", "low", 30*time.Minute)
+
+	from := time.Date(2030, 10, 11, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, 1)
+	slots, err := FreeSlots([]*Calendar{mine, theirs}, from, to, time.Hour, DefaultWorkingHours)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []Slot{
+		{Start: from.Add(9 * time.Hour), End: from.Add(10 * time.Hour)},
+		{Start: from.Add(11 * time.Hour), End: from.Add(13 * time.Hour)},
+		{Start: from.Add(13*time.Hour + 30*time.Minute), End: from.Add(18 * time.Hour)},
+	}
+	if len(slots) != len(expected) {
+		t.Fatalf("Expected %d slots, got %v", len(expected), slots)
+	}
+	for i := range expected {
+		if !slots[i].Start.Equal(expected[i].Start) || !slots[i].End.Equal(expected[i].End) {
+			t.Errorf("Expected slot %v, got %v", expected[i], slots[i])
+		}
+	}
+}
+
+func TestParseWorkingHours(t *testing.T) {
+	h, err := ParseWorkingHours("08:30-17:00")
+	if err != nil || h.Start != 8*time.Hour+30*time.Minute || h.End != 17*time.Hour {
+		t.Errorf("Expected 08:30-17:00, got %v (%v)", h, err)
+	}
+	if _, err := ParseWorkingHours("18:00-09:00"); err == nil {
+		t.Error("Expected an error for reversed hours, got none")
+	}
+}
+
+func TestFreeSlotsOnDaylightSavingDay(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// 30 марта 2025 года в Берлине часы переводятся с 02:00 на 03:00
+	from := time.Date(2025, 3, 30, 0, 0, 0, 0, berlin)
+	slots, err := FreeSlots([]*Calendar{NewCalendar(nil)}, from, from.AddDate(0, 0, 1), time.Hour, DefaultWorkingHours)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	start, end := time.Date(2025, 3, 30, 9, 0, 0, 0, berlin), time.Date(2025, 3, 30, 18, 0, 0, 0, berlin)
+	if len(slots) != 1 || !slots[0].Start.Equal(start) || !slots[0].End.Equal(end) {
+		t.Errorf("Expected working hours 09:00-18:00 by the wall clock, got %v", slots)
+	}
+}
@@ -0,0 +1,43 @@
+package calendar
+
+import (
+	"github.com/elizavetanr/myDays/logger"
+	"github.com/elizavetanr/myDays/reminder"
+)
+
+// SetInbox задает историю доставленных напоминаний. Ее нужно задать до Start.
+func (c *Calendar) SetInbox(inbox *reminder.Inbox) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inbox = inbox
+}
+
+func (c *Calendar) Inbox() *reminder.Inbox {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.inbox
+}
+
+// dispatch доставляет напоминание всеми настроенными способами
+// и записывает его во входящие. Способы, отправляющие напоминания в фоне,
+// записываются после доставки в recordDelivery.
+func (c *Calendar) dispatch(n reminder.Notification) {
+	delivered, err := c.notifiers.Deliver(n)
+	if err != nil {
+		logger.Error("Ошибка доставки напоминания: " + err.Error())
+	}
+	if len(delivered) == 0 {
+		return
+	}
+	if err := c.Inbox().Record(n, delivered); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
+// recordDelivery записывает во входящие способ доставки, который
+// отправил напоминание в фоне.
+func (c *Calendar) recordDelivery(n reminder.Notification, channel string) {
+	if err := c.Inbox().Record(n, []string{channel}); err != nil {
+		logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,127 @@
+package calendar
+
+import (
+	"errors"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/timeutil"
+	"slices"
+	"sort"
+	"time"
+)
+
+var (
+	ErrInvalidSortKey = errors.New("некорректный ключ сортировки")
+)
+
+type SortKey string
+
+const (
+	SortByDate     SortKey = "date"
+	SortByPriority SortKey = "priority"
+	SortByTitle    SortKey = "title"
+)
+
+func (k SortKey) Validate() error {
+	switch k {
+	case "", SortByDate, SortByPriority, SortByTitle:
+		return nil
+	default:
+		return ErrInvalidSortKey
+	}
+}
+
+type When int
+
+const (
+	WhenAny When = iota
+	WhenUpcoming
+	WhenPast
+)
+
+// Query описывает выборку событий. Нулевые значения полей не ограничивают выборку,
+// по умолчанию события сортируются по времени начала.
+type Query struct {
+	From        time.Time
+	To          time.Time
+	Priorities  []events.Priority
+	HasReminder bool
+	When        When
+	SortBy      SortKey
+	Limit       int
+}
+
+func (c *Calendar) Query(q Query) ([]*events.Event, error) {
+	if err := q.SortBy.Validate(); err != nil {
+		return nil, err
+	}
+	for _, p := range q.Priorities {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	now := c.clock.Now()
+	var result []*events.Event
+	c.mu.RLock()
+	for _, e := range c.sortedEvents() {
+		if q.matches(e, now) {
+			result = append(result, e.Clone())
+		}
+	}
+	c.mu.RUnlock()
+
+	switch q.SortBy {
+	case SortByPriority:
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].Priority.Rank() > result[j].Priority.Rank()
+		})
+	case SortByTitle:
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].Title < result[j].Title
+		})
+	}
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+	return result, nil
+}
+
+func (q Query) matches(e *events.Event, now time.Time) bool {
+	if !q.From.IsZero() && e.StartAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !e.StartAt.Before(q.To) {
+		return false
+	}
+	if len(q.Priorities) > 0 && !slices.Contains(q.Priorities, e.Priority) {
+		return false
+	}
+	if q.HasReminder && len(e.Reminders) == 0 {
+		return false
+	}
+	switch q.When {
+	case WhenUpcoming:
+		return !e.StartAt.Before(now)
+	case WhenPast:
+		return e.StartAt.Before(now)
+	}
+	return true
+}
+
+func DayRange(t time.Time) (time.Time, time.Time) {
+	from := timeutil.StartOfDay(t)
+	return from, from.AddDate(0, 0, 1)
+}
+
+// WeekRange возвращает границы недели, начинающейся с понедельника.
+func WeekRange(t time.Time) (time.Time, time.Time) {
+	offset := (int(t.Weekday()) + 6) % 7
+	from := timeutil.StartOfDay(t).AddDate(0, 0, -offset)
+	return from, from.AddDate(0, 0, 7)
+}
+
+func MonthRange(t time.Time) (time.Time, time.Time) {
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return from, from.AddDate(0, 1, 0)
+}
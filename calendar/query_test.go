@@ -0,0 +1,37 @@
+package calendar
+
+import (
+	"github.com/elizavetanr/myDays/events"
+	"testing"
+	"time"
+)
+
+func TestQuerySortsAndFilters(t *testing.T) {
+	c := NewCalendar(nil)
+	c.AddEvent("Третье", "2030-10-13 10:00", events.PriorityHigh, 0)
+	c.AddEvent("Первое", "2030-10-11 10:00", events.PriorityLow, 0)
+	c.AddEvent("Второе", "2030-10-12 10:00", events.PriorityHigh, 0)
+
+	all, err := c.Query(Query{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(all) != 3 || all[0].Title != "Первое" || all[2].Title != "Третье" {
+		t.Errorf("Expected chronological order, got %v %v %v", all[0].Title, all[1].Title, all[2].Title)
+	}
+
+	high, _ := c.Query(Query{Priorities: []events.Priority{events.PriorityHigh}, Limit: 1})
+	if len(high) != 1 || high[0].Title != "Второе" {
+		t.Errorf("Expected earliest high priority event, got %v", high)
+	}
+
+	from, to := DayRange(time.Date(2030, 10, 12, 15, 0, 0, 0, time.Local))
+	day, _ := c.Query(Query{From: from, To: to})
+	if len(day) != 1 || day[0].Title != "Второе" {
+		t.Errorf("Expected one event for the day, got %v", day)
+	}
+
+	if _, err := c.Query(Query{SortBy: "color"}); err != ErrInvalidSortKey {
+		t.Errorf("Expected ErrInvalidSortKey, got %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+package calendar
+
+import (
+	"github.com/elizavetanr/myDays/events"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	FieldTitle    = "title"
+	FieldReminder = "reminder"
+)
+
+// Match - найденный фрагмент поля события. Start и End - индексы рун в Text.
+type Match struct {
+	Field string
+	Text  string
+	Start int
+	End   int
+}
+
+type SearchResult struct {
+	Event   *events.Event
+	Score   float64
+	Matches []Match
+}
+
+type searchField struct {
+	name   string
+	text   string
+	weight float64
+}
+
+// searchableFields перечисляет текстовые поля события, участвующие в поиске.
+// Новые текстовые поля события достаточно добавить сюда. Напоминания
+// ищутся по тексту с подставленным шаблоном, как их видит пользователь.
+func searchableFields(e *events.Event) []searchField {
+	fields := []searchField{{name: FieldTitle, text: e.Title, weight: 1}}
+	for _, r := range e.Reminders {
+		fields = append(fields, searchField{name: FieldReminder, text: e.ReminderText(r, r.At), weight: 0.8})
+	}
+	return fields
+}
+
+// Search ищет события по всем текстовым полям без учета регистра (в том числе
+// для кириллицы, "ё" и "е" считаются одной буквой) и с допуском опечаток.
+// Результаты упорядочены по убыванию релевантности.
+func (c *Calendar) Search(query string) []SearchResult {
+	queryRunes := normalize(query)
+	tokens := splitWords(queryRunes)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var results []SearchResult
+	for _, e := range c.sortedEvents() {
+		fields := searchableFields(e)
+		total := 0.0
+		var matches []Match
+		for _, token := range tokens {
+			best, match := 0.0, Match{}
+			for _, f := range fields {
+				score, start, end := matchToken(token.runes(queryRunes), normalize(f.text))
+				if score*f.weight > best {
+					best = score * f.weight
+					match = Match{Field: f.name, Text: f.text, Start: start, End: end}
+				}
+			}
+			if best == 0 {
+				total = 0
+				break
+			}
+			total += best
+			matches = append(matches, match)
+		}
+		if total == 0 {
+			continue
+		}
+		score := total / float64(len(tokens))
+		for _, f := range fields {
+			if len(tokens) > 1 && strings.Contains(string(normalize(f.text)), string(queryRunes)) {
+				score += 0.25 * f.weight
+				break
+			}
+		}
+		results = append(results, SearchResult{Event: e.Clone(), Score: score, Matches: matches})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// matchToken возвращает оценку совпадения слова запроса с текстом поля
+// и границы лучшего совпадения в рунах.
+func matchToken(token, text []rune) (float64, int, int) {
+	best, bestStart, bestEnd := 0.0, 0, 0
+	for _, w := range splitWords(text) {
+		word := w.runes(text)
+		ws, ts := string(word), string(token)
+		score, start, end := 0.0, w.start, w.end
+		switch {
+		case ws == ts:
+			score = 1
+		case strings.HasPrefix(ws, ts):
+			score, end = 0.9, w.start+len(token)
+		case strings.Contains(ws, ts):
+			offset := utf8.RuneCountInString(ws[:strings.Index(ws, ts)])
+			score, start, end = 0.7, w.start+offset, w.start+offset+len(token)
+		default:
+			limit := typoLimit(len(token))
+			if limit == 0 {
+				continue
+			}
+			distance := levenshtein(token, word)
+			if len(word) > len(token) {
+				if d := levenshtein(token, word[:len(token)]); d < distance {
+					distance, end = d, w.start+len(token)
+				}
+			}
+			if distance > limit {
+				continue
+			}
+			score = 0.6 * (1 - float64(distance)/float64(limit+1))
+		}
+		if score > best {
+			best, bestStart, bestEnd = score, start, end
+		}
+	}
+	return best, bestStart, bestEnd
+}
+
+func typoLimit(length int) int {
+	switch {
+	case length < 4:
+		return 0
+	case length < 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func normalize(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		r = unicode.ToLower(r)
+		if r == 'ё' {
+			r = 'е'
+		}
+		runes[i] = r
+	}
+	return runes
+}
+
+type wordSpan struct {
+	start int
+	end   int
+}
+
+func (w wordSpan) runes(text []rune) []rune {
+	return text[w.start:w.end]
+}
+
+func splitWords(text []rune) []wordSpan {
+	var words []wordSpan
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		}
+		if !isWord && start >= 0 {
+			words = append(words, wordSpan{start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, wordSpan{start: start, end: len(text)})
+	}
+	return words
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,51 @@
+package calendar
+
+import (
+	"testing"
+)
+
+func TestSearchIsCaseInsensitiveAndTypoTolerant(t *testing.T) {
+	c := NewCalendar(nil)
+	c.AddEvent("Елка в офисе", "2030-12-25 18:00", "low", 0)
+	c.AddEvent("Созвон с командой", "2030-10-11 10:00", "low", 0)
+	c.AddEvent("Поход к врачу", "2030-10-12 10:00", "low", 0)
+
+	results := c.Search("ЁЛКА")
+	if len(results) != 1 || results[0].Event.Title != "Елка в офисе" {
+		t.Fatalf("Expected to find event by case and ё-insensitive query, got %v", results)
+	}
+	match := results[0].Matches[0]
+	if match.Field != FieldTitle || match.Start != 0 || match.End != 4 {
+		t.Errorf("Expected match of first word, got %+v", match)
+	}
+
+	results = c.Search("камандой")
+	if len(results) != 1 || results[0].Event.Title != "Созвон с командой" {
+		t.Errorf("Expected to find event despite typo, got %v", results)
+	}
+
+	if results := c.Search("отпуск"); len(results) != 0 {
+		t.Errorf("Expected no results, got %v", results)
+	}
+}
+
+func TestSearchMatchesRenderedReminderText(t *testing.T) {
+	c := NewCalendar(nil)
+	e, _ := c.AddEvent("Созвон с командой", "2030-10-11 10:00", "low", 0)
+	if _, err := c.SetEventReminder(e.ID, "Не забудь: {{.Title}}", "1h"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	c.AddEvent("Поход к врачу", "2030-10-12 10:00", "low", 0)
+
+	if results := c.Search("title"); len(results) != 0 {
+		t.Errorf("Expected template actions not to be searched, got %v", results)
+	}
+	results := c.Search("забудь созвон")
+	if len(results) != 1 || results[0].Event.ID != e.ID {
+		t.Fatalf("Expected to find event by rendered reminder, got %v", results)
+	}
+	match := results[0].Matches[0]
+	if match.Field != FieldReminder || match.Text != "Не забудь: Созвон с командой" || match.Start != 3 || match.End != 9 {
+		t.Errorf("Expected match in rendered reminder text, got %+v", match)
+	}
+}
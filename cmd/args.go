@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrUnknownOption      = errors.New("неизвестная опция")
+	ErrMissingOptionValue = errors.New("не указано значение опции")
+	ErrMissingArguments   = errors.New("недостаточно аргументов")
+	ErrUnexpectedArgument = errors.New("лишний аргумент")
+)
+
+// args - разобранные аргументы команды: позиционные значения,
+// именованные опции вида --name value (или --name=value) и флаги без значения.
+type args struct {
+	positional []string
+	options    map[string]string
+	flags      map[string]bool
+}
+
+func parseArgs(parts []string, options []string, flags []string) (*args, error) {
+	a := &args{
+		options: make(map[string]string),
+		flags:   make(map[string]bool),
+	}
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+		if !strings.HasPrefix(part, "--") || len(part) == 2 {
+			a.positional = append(a.positional, part)
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(part, "--"), "=")
+		switch {
+		case slices.Contains(flags, name):
+			if hasValue {
+				return nil, fmt.Errorf("--%s: %w", name, ErrUnknownOption)
+			}
+			a.flags[name] = true
+		case slices.Contains(options, name):
+			if !hasValue {
+				if i+1 >= len(parts) {
+					return nil, fmt.Errorf("--%s: %w", name, ErrMissingOptionValue)
+				}
+				i++
+				value = parts[i]
+			}
+			a.options[name] = value
+		default:
+			return nil, fmt.Errorf("--%s: %w", name, ErrUnknownOption)
+		}
+	}
+	return a, nil
+}
+
+func (a *args) option(name string) (string, bool) {
+	value, ok := a.options[name]
+	return value, ok
+}
+
+func (a *args) hasOptions() bool {
+	return len(a.options) > 0 || len(a.flags) > 0
+}
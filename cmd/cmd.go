@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/c-bata/go-prompt"
@@ -8,10 +9,13 @@ import (
 	"github.com/elizavetanr/myDays/events"
 	"github.com/elizavetanr/myDays/logger"
 	"github.com/elizavetanr/myDays/reminder"
+	"github.com/elizavetanr/myDays/timers"
+	"github.com/elizavetanr/myDays/timeutil"
 	"github.com/google/shlex"
 	"os"
 	"strings"
 	"sync"
+	"time"
 )
 
 var (
@@ -22,235 +26,663 @@ type Cmd struct {
 	calendar *calendar.Calendar
 	log      []string
 	mu       sync.Mutex
+	display  *display
+	timers   *timers.Exporter
+	remote   Remote
+	json     bool
+	lockPath string
+	// interactive включается в интерактивном режиме, когда вывод читает
+	// человек в терминале.
+	interactive bool
 }
 
 func NewCmd(c *calendar.Calendar) *Cmd {
 	return &Cmd{
 		calendar: c,
 		log:      []string{},
+		display:  newDisplay(os.Stdout, c.Inbox),
 	}
 }
+
+// SetBell включает звуковой сигнал терминала для напоминаний с высоким приоритетом.
+func (c *Cmd) SetBell(enabled bool) {
+	c.display.setBell(enabled)
+}
 func (c *Cmd) executor(input string) {
+	c.display.setInput(prompt.Document{})
 	input = strings.TrimSpace(input)
-	var output string
 	if input == "" {
-		output = "Вы ввели пустую строку. 'help' для списка команд"
-		c.logIOHistory(output)
+		c.logIOHistory("Вы ввели пустую строку. 'help' для списка команд")
 		return
 	}
 	c.logIOHistory(input)
 
 	parts, err := shlex.Split(input)
-	if err != nil {
-		output = "Некорректный ввод команды"
-		c.logIOHistory(output)
+	if err != nil || len(parts) == 0 {
+		if err == nil {
+			err = ErrMissingArguments
+		}
 		c.logError("Ошибка парсинга ввода: " + err.Error())
+		c.show(failed("Некорректный ввод команды", ErrUsage))
+		return
+	}
+	switch strings.ToLower(parts[0]) {
+	case "log":
+		c.showLogIOHistory()
+	case "exit":
+		c.exit()
+	case "json":
+		c.show(c.toggleJSON(parts[1:]))
+	default:
+		c.show(c.run(parts))
+	}
+}
+
+// show выводит результат команды текстом или одной строкой JSON.
+func (c *Cmd) show(result Result) {
+	if c.json {
+		c.logIOHistory(result.JSON())
+		return
+	}
+	c.logIOHistory(result.Message)
+}
+
+// SetJSON включает вывод результатов команд в формате JSON.
+func (c *Cmd) SetJSON(enabled bool) {
+	c.json = enabled
+}
+
+func (c *Cmd) toggleJSON(parts []string) Result {
+	result := failed("Формат: json on|off", ErrUsage)
+	switch {
+	case len(parts) != 1:
+	case parts[0] == "on":
+		c.json = true
+		result = done("Вывод в формате JSON включен")
+	case parts[0] == "off":
+		c.json = false
+		result = done("Вывод в формате JSON выключен")
+	}
+	result.Command = "json"
+	return result
+}
+
+// exit сохраняет календарь и завершает приложение. Клиент демона
+// только закрывается, календарь остается у демона.
+func (c *Cmd) exit() {
+	if c.remote != nil {
+		c.logIOHistory("Сеанс завершен, напоминания продолжит отправлять демон")
+		c.logInfo("Клиент демона закрыт")
+		os.Exit(0)
+	}
+	if err := c.calendar.Save(); err != nil {
+		c.logIOHistory("Сохранение не выполнено")
+		c.logError(err.Error())
+	} else {
+		c.logIOHistory("Сохранено")
+		c.logInfo("Выполнено сохранение календаря")
 	}
+	c.calendar.Stop()
+	c.logInfo("Приложение закрыто")
+	os.Exit(0)
+}
 
+// Execute выполняет команду над календарем и возвращает ее результат. parts -
+// имя команды и ее аргументы, уже разобранные как в строке ввода.
+func (c *Cmd) Execute(parts []string) Result {
+	var result Result
 	cmd := strings.ToLower(parts[0])
-	c.calendar.StartAllReminder()
+	args := parts[1:]
 	switch cmd {
 	case "add":
-		if len(parts) < 4 {
-			output = "Формат: add \"название события\" \"дата и время\" \"приоритет\""
-			c.logIOHistory(output)
-			return
-		}
-
-		title := parts[1]
-		date := parts[2]
-		priority := events.Priority(parts[3])
+		result = c.add(args)
+	case "update":
+		result = c.update(args)
+	case "remove":
+		result = c.remove(args)
+	case "list":
+		result = c.list(args)
+	case "search":
+		result = c.search(args)
+	case "conflicts":
+		result = c.conflicts(args)
+	case "free":
+		result = c.free(args)
+	case "add_reminder":
+		result = c.addReminder(args)
+	case "remove_reminder":
+		result = c.removeReminder(args)
+	case "reminders":
+		result = c.reminders(args)
+	case "ack":
+		result = c.ack(args)
+	case "snooze":
+		result = c.snooze(args)
+	case "pending":
+		result = c.pending()
+	case "dnd":
+		result = c.doNotDisturb(args)
+	case "held":
+		result = c.held()
+	case "inbox":
+		result = c.inbox(args)
+	case "export_timers":
+		result = c.exportTimers(args)
+	case "digest":
+		result = c.digest(args)
+	case "cancel":
+		result = c.cancel(args)
+	case "help":
+		result = done(helpText)
+	default:
+		result = failed("Неизвестная команда. Введите 'help' для списка команд", ErrUnknownCommand)
+	}
+	if changesReminders[cmd] && result.OK {
+		c.syncTimers()
+	}
+	result.Command = cmd
+	return result
+}
 
-		event, err := c.calendar.AddEvent(title, date, priority)
+func (c *Cmd) add(parts []string) Result {
+	a, err := parseArgs(parts, []string{"duration", "reminders"}, nil)
+	if err != nil || len(a.positional) < 3 {
+		return failed("Формат: add \"название события\" \"дата и время\" \"приоритет\" [--duration \"длительность\"]"+
+			" [--reminders \"1d,1h\"|none|default]", ErrUsage)
+	}
 
+	title := a.positional[0]
+	date := a.positional[1]
+	priority := events.Priority(a.positional[2])
+	var duration time.Duration
+	if value, ok := a.option("duration"); ok {
+		duration, err = timeutil.ParseDuration(value)
 		if err != nil {
-			switch {
-			case errors.Is(err, events.ErrInvalidPriority):
-				output = fmt.Sprintf("Некорректный приоритет. Возможные приоритеты: \"%s\", \"%s\", \"%s\"",
-					events.PriorityLow, events.PriorityMedium, events.PriorityHigh)
-			case errors.Is(err, events.ErrInvalidTitle):
-				output = "Некорректное название события. Длина названия от 3 до 50 символов." +
-					"\nНазвание может состоять из букв русского и английского алфавита, цифр, пробелов и точек."
-			case errors.Is(err, events.ErrInvalidDate):
-				output = "Некорректный формат даты. Пример правильного формата: \"2025-10-11 15:00\""
-			}
-			c.logError(err.Error())
-		} else {
-			output = "Событие добавлено"
-			c.logInfo(fmt.Sprintf("Добавлено событие: ID - %s Title - %s Date - %s Priority - %s ",
-				event.ID, event.Title, event.StartAt.Format("02.01.2006  15:04:05"), string(event.Priority)))
+			return failed("Некорректный ввод длительности. Примеры правильного ввода: \"1h\", \"1h30m\", \"45m\"", err)
 		}
-
-	case "update":
-		if len(parts) < 5 {
-			output = "Формат: update \"ID события\" \"название события\" \"дата и время\" \"приоритет\""
-			c.logIOHistory(output)
-			return
-		}
-		ID := parts[1]
-		title := parts[2]
-		date := parts[3]
-		priority := events.Priority(parts[4])
-		err = c.calendar.EditEvent(ID, title, date, priority)
-		if err != nil {
-			switch {
-			case errors.Is(err, calendar.ErrEventNotFound):
-				output = "Событие с введенным id не найдено"
-			case errors.Is(err, events.ErrInvalidPriority):
-				output = fmt.Sprintf("Некорректный приоритет. Возможные приоритеты: \"%s\", \"%s\", \"%s\"",
-					events.PriorityLow, events.PriorityMedium, events.PriorityHigh)
-			case errors.Is(err, events.ErrInvalidTitle):
-				output = "Некорректное название события. Длина названия от 3 до 50 символов." +
-					"\nНазвание может состоять из букв русского и английского алфавита, цифр, пробелов и точек."
-			case errors.Is(err, events.ErrInvalidDate):
-				output = "Некорректный формат даты. Пример правильного формата: \"2025-10-11 15:00\""
-			}
-			c.logError(err.Error())
-		} else {
-			output = "Событие изменено"
-			c.logInfo(fmt.Sprintf("Изменено событие с ID - %s: Title - %s Date - %s Priority - %s ",
-				ID, title, date, priority))
+	}
+	var defaults *events.ReminderDefaults
+	if value, ok := a.option("reminders"); ok {
+		if defaults, err = parseReminderDefaults(value); err != nil {
+			return failed(reminderDefaultsUsage, err)
 		}
+	}
 
-	case "remove":
-		if len(parts) < 2 {
-			output = "Формат: remove \"ID события\""
-			c.logIOHistory(output)
-			return
+	event, err := c.calendar.AddEvent(title, date, priority, duration)
+	if err == nil && defaults != nil {
+		var result *calendar.EditResult
+		if result, err = c.calendar.PatchEvent(event.ID, events.EventPatch{ReminderDefaults: defaults}); err == nil {
+			event = result.Event
 		}
-		ID := parts[1]
-		err = c.calendar.DeleteEvent(ID)
-		if errors.Is(err, calendar.ErrEventNotFound) {
+	}
+	if err != nil {
+		c.logError(err.Error())
+		return failed(describeEventError(err), err)
+	}
+
+	output := "Событие добавлено. ID: " + event.ID
+	if count := countAutoReminders(event); count > 0 {
+		output += fmt.Sprintf("\nДобавлено напоминаний по умолчанию: %d", count)
+	}
+	output += c.conflictWarning(event.ID)
+	c.logInfo(fmt.Sprintf("Добавлено событие: ID - %s Title - %s Date - %s Priority - %s ",
+		event.ID, event.Title, event.StartAt.Format("02.01.2006  15:04:05"), string(event.Priority)))
+	return c.eventResult(output, event)
+}
+
+func (c *Cmd) update(parts []string) Result {
+	if len(parts) < 2 {
+		return failed("Формат: update \"ID события\" [--title \"название\"] [--date \"дата и время\"] [--priority \"приоритет\"] [--duration \"длительность\"]"+
+			" [--reminders \"1d,1h\"|none|default]"+
+			"\nили: update \"ID события\" \"название события\" \"дата и время\" \"приоритет\"", ErrUsage)
+	}
+	ID := parts[0]
+	patch, err := parseEventPatch(parts[1:])
+	if err != nil {
+		return failed("Некорректные аргументы команды update: "+err.Error(), err)
+	}
+	result, err := c.calendar.PatchEvent(ID, patch)
+	if err != nil {
+		c.logError(err.Error())
+		return failed(describeEventError(err), err)
+	}
+	if len(result.Changes) == 0 {
+		return c.eventResult("Изменений нет", result.Event)
+	}
+
+	output := "Событие изменено:"
+	for _, change := range result.Changes {
+		output += fmt.Sprintf("\n  %s: %s -> %s", fieldNames[change.Field], change.Old, change.New)
+	}
+	for _, r := range result.Rescheduled {
+		output += fmt.Sprintf("\nНапоминание \"%s\" перенесено на %s", result.Event.ReminderText(r, r.At), r.At.Format(events.DateFormat))
+	}
+	for _, r := range result.AutoAdded {
+		output += fmt.Sprintf("\nДобавлено напоминание по умолчанию на %s", r.At.Format(events.DateFormat))
+	}
+	for _, r := range result.AutoRemoved {
+		output += fmt.Sprintf("\nУдалено напоминание по умолчанию на %s", r.At.Format(events.DateFormat))
+	}
+	for _, r := range result.Stale {
+		output += fmt.Sprintf("\nВнимание, напоминание \"%s\" теперь приходится на прошедшее время %s и не будет отправлено",
+			result.Event.ReminderText(r, r.At), r.At.Format(events.DateFormat))
+	}
+	output += c.conflictWarning(ID)
+	c.logInfo(fmt.Sprintf("Изменено событие с ID - %s: %s", ID, formatChanges(result.Changes)))
+	return c.eventResult(output, result.Event)
+}
+
+func (c *Cmd) remove(parts []string) Result {
+	if len(parts) < 1 {
+		return failed("Формат: remove \"ID события\"", ErrUsage)
+	}
+	ID := parts[0]
+	if err := c.calendar.DeleteEvent(ID); err != nil {
+		c.logError(err.Error())
+		return failed("Событие с введенным id не найдено", err)
+	}
+	c.logInfo(fmt.Sprintf("Удалено событие с ID - %s", ID))
+	return done("Событие удалено")
+}
+
+func (c *Cmd) addReminder(parts []string) Result {
+	if len(parts) < 3 {
+		return failed("Формат: add_reminder \"ID события\" \"текст или шаблон напоминания\" \"интервал до события или дата и время\"", ErrUsage)
+	}
+	ID := parts[0]
+	message := parts[1]
+	when := parts[2]
+	r, err := c.calendar.SetEventReminder(ID, message, when)
+	if err != nil {
+		c.logError(err.Error())
+		var output string
+		switch {
+		case errors.Is(err, reminder.ErrTimeReminderIsUp):
+			output = "Нельзя запустить напоминание с истекшим временем"
+		case errors.Is(err, calendar.ErrEventNotFound):
 			output = "Событие с введенным id не найдено"
-			c.logError(err.Error())
-		} else {
-			output = "Событие удалено"
-			c.logInfo(fmt.Sprintf("Удалено событие с ID - %s", ID))
-		}
-	case "list":
-		calendarEvents := c.calendar.GetEvent()
-		if len(calendarEvents) == 0 {
-			output = "Список событий пуст"
-			c.logIOHistory(output)
-			return
-		}
-		output = ""
-		for _, event := range calendarEvents {
-			dateStr := event.StartAt.Format("2006-01-02 15:04")
-			outputEvent := event.Title + " - " + dateStr + " - ID: " + event.ID
-			output += outputEvent + "\n"
+		case errors.Is(err, calendar.ErrInvalidDuration):
+			output = "Некорректный ввод времени напоминания. Примеры правильного ввода: \"2h45m\", \"1.5h\", \"1d2h\", \"2w\"" +
+				" или дата и время \"2025-10-10 20:00\""
+		case errors.Is(err, calendar.ErrEventExpired):
+			output = "Нельзя добавить напоминание прошедшему событию"
+		case errors.Is(err, calendar.ErrReminderTimeAfterEvent):
+			output = "Нельзя добавить напоминание после начала события"
+		case errors.Is(err, calendar.ErrReminderTimeBeforeNow):
+			output = "Нельзя добавить напоминание раньше текущего времени"
+		case errors.Is(err, reminder.ErrInvalidTemplate):
+			output = reminderTemplateUsage
+		default:
+			output = "Ошибка: " + err.Error()
 		}
-	case "add_reminder":
-		if len(parts) < 4 {
-			output = "Формат: add_reminder \"ID события\" \"текст напоминания\" \"интервал до события\""
-			c.logIOHistory(output)
-			return
+		return failed(output, err)
+	}
+	c.logInfo(fmt.Sprintf("Добавлено напоминание %s к событию с ID - %s: Message - %s At - %s",
+		r.ID, ID, message, r.At.Format(events.DateFormat)))
+	return c.eventResult("Напоминание на "+r.At.Format(events.DateFormat)+" добавлено и запущено. ID напоминания: "+r.ID, c.findEvent(ID))
+}
+
+func (c *Cmd) removeReminder(parts []string) Result {
+	if len(parts) < 1 {
+		return failed("Формат: remove_reminder \"ID события\" [\"ID напоминания\"]", ErrUsage)
+	}
+	ID := parts[0]
+	reminderID := ""
+	if len(parts) > 1 {
+		reminderID = parts[1]
+	}
+	removed, err := c.calendar.CancelEventReminder(ID, reminderID)
+	if err != nil {
+		c.logError(err.Error())
+		var output string
+		switch {
+		case errors.Is(err, calendar.ErrEventNotFound):
+			output = "Событие с введенным id не найдено"
+		case errors.Is(err, calendar.ErrReminderNotSpecified):
+			output = "У события несколько напоминаний, укажите ID напоминания. Список: reminders \"ID события\""
+		case errors.Is(err, reminder.ErrNotExistReminder):
+			output = "У этого события не существует такого напоминания"
+		default:
+			output = "Ошибка: " + err.Error()
 		}
-		ID := parts[1]
-		message := parts[2]
-		before := parts[3]
-		err = c.calendar.SetEventReminder(ID, message, before)
+		return failed(output, err)
+	}
+	c.logInfo(fmt.Sprintf("Удалено напоминание %s у события с ID - %s", removed.ID, ID))
+	return c.eventResult("Напоминание удалено", c.findEvent(ID))
+}
+
+func (c *Cmd) ack(parts []string) Result {
+	if len(parts) < 1 {
+		return failed("Формат: ack \"ID напоминания\"", ErrUsage)
+	}
+	pending, err := c.calendar.AcknowledgeReminder(parts[0])
+	if err != nil {
+		c.logError(err.Error())
+		return failed(describeReminderStateError(err), err)
+	}
+	c.logInfo(fmt.Sprintf("Подтверждено напоминание %s у события с ID - %s", pending.Reminder.ID, pending.Event.ID))
+	return c.eventResult("Напоминание подтверждено: "+pending.Event.ReminderText(pending.Reminder, pending.Reminder.At), pending.Event)
+}
+
+func (c *Cmd) snooze(parts []string) Result {
+	if len(parts) < 2 {
+		return failed("Формат: snooze \"ID напоминания\" \"интервал\"", ErrUsage)
+	}
+	d, err := timeutil.ParseDuration(parts[1])
+	if err != nil {
+		return failed("Некорректный ввод интервала. Примеры правильного ввода: \"10m\", \"1h\", \"1d\"", err)
+	}
+	pending, err := c.calendar.SnoozeReminder(parts[0], d)
+	if err != nil {
+		c.logError(err.Error())
+		return failed(describeReminderStateError(err), err)
+	}
+	c.logInfo(fmt.Sprintf("Отложено напоминание %s у события с ID - %s до %s",
+		pending.Reminder.ID, pending.Event.ID, pending.Reminder.NextAt().Format(events.DateFormat)))
+	return c.eventResult("Напоминание отложено до "+pending.Reminder.NextAt().Format(events.DateFormat), pending.Event)
+}
+
+const helpText = "Доступные команды:" +
+	"\nДобавление события: add \"название события\" \"дата и время\" \"приоритет\" [--duration \"длительность\"]" +
+	" [--reminders \"1d,1h\"|none|default]" +
+	"\nРедактирование события: update \"ID события\" \"название события\" \"дата и время\" \"приоритет\"" +
+	"\nЧастичное редактирование: update \"ID события\" [--title \"название\"] [--date \"дата и время\"] [--priority \"приоритет\"] [--duration \"длительность\"]" +
+	" [--reminders \"1d,1h\"|none|default]" +
+	"\nУдаление события: remove \"ID события\"" +
+	"\nОтмена события: cancel \"ID события\"" +
+	"\nДобавление напоминания: add_reminder \"ID события\" \"текст напоминания\" \"интервал до события (2h, 1d2h, 2w) или дата и время\"" +
+	"\n  В тексте можно использовать {{.Title}}, {{.Until}}, {{.Priority}} и {{.Date}}, пустой текст - шаблон по умолчанию" +
+	"\nУдаление напоминания: remove_reminder \"ID события\" [\"ID напоминания\"]" +
+	"\nСписок напоминаний события: reminders \"ID события\"" +
+	"\nСработавшие неподтвержденные напоминания: pending" +
+	"\nПодтвердить напоминание: ack \"ID напоминания\"" +
+	"\nОтложить напоминание: snooze \"ID напоминания\" \"интервал\"" +
+	"\nРежим \"не беспокоить\": dnd [\"интервал\"|off]" +
+	"\nЗадержанные напоминания: held" +
+	"\nЭкспорт напоминаний во внешние таймеры: export_timers [--format systemd|at] [--dir \"каталог\"] [--activate]" +
+	"\nВходящие напоминания: inbox [--all] [--from \"дата\"] [--to \"дата\"]" +
+	"\nСводка на сегодня: digest [--tomorrow]" +
+	"\nВывести список событий: list [today|week|month] [--from \"дата\"] [--to \"дата\"] [--priority \"high,medium\"]" +
+	"\n  [--reminder] [--past|--upcoming] [--sort date|priority|title] [--limit N]" +
+	"\nПоиск событий: search \"запрос\" [--limit N]" +
+	"\nПересечения событий: conflicts [--from \"дата\"] [--to \"дата\"]" +
+	"\nПоиск свободного времени: free \"с даты\" \"по дату\" \"длительность\" [--hours \"09:00-18:00\"] [--with \"calendar2.json,calendar3.zip\"] [--limit N]" +
+	"\nВывести список всех команд: help" +
+	"\nВывести логи: log" +
+	"\nВывод в формате JSON: json on|off" +
+	"\nВыход из приложения: exit" +
+	"\nБез интерактивного режима: myDays [--json] команда [аргументы] или команды по одной на строку через stdin" +
+	"\nФоновый режим с напоминаниями: myDays daemon"
+
+var fieldNames = map[string]string{
+	"title":     "название",
+	"date":      "дата",
+	"priority":  "приоритет",
+	"duration":  "длительность",
+	"reminders": "напоминания по умолчанию",
+}
+
+const reminderTemplateUsage = "Некорректный шаблон напоминания. Пример: \"{{.Title}} начнется через {{.Until}} ({{.Priority}})\"." +
+	"\nДоступные поля: {{.Title}}, {{.Until}}, {{.Priority}}, {{.Date}}"
+
+const reminderDefaultsUsage = "Некорректное значение --reminders. Примеры: \"1d,1h\", \"30m\", \"none\", \"default\""
+
+// parseReminderDefaults разбирает значение опции --reminders: список интервалов,
+// "none" - без напоминаний по умолчанию, "default" - по политике приоритета.
+func parseReminderDefaults(value string) (*events.ReminderDefaults, error) {
+	switch value {
+	case "default":
+		return &events.ReminderDefaults{}, nil
+	case "none":
+		return &events.ReminderDefaults{Custom: true}, nil
+	}
+	defaults := &events.ReminderDefaults{Custom: true}
+	for _, part := range strings.Split(value, ",") {
+		offset, err := timeutil.ParseDuration(part)
 		if err != nil {
-			switch {
-			case errors.Is(err, reminder.ErrTimeReminderIsUp):
-				output = "Нельзя запустить напоминание с истекшим временем"
-			case errors.Is(err, calendar.ErrEventNotFound):
-				output = "Событие с введенным id не найдено"
-			case errors.Is(err, calendar.ErrInvalidDuration):
-				output = "Некорректный ввод интервала. Примеры правильного ввода: \"2h45m\", \"1.5h\", \"120m\""
-			case errors.Is(err, calendar.ErrEventExpired):
-				output = "Нельзя добавить напоминание прошедшему событию"
-			case errors.Is(err, calendar.ErrReminderTimeAfterEvent):
-				output = "Нельзя добавить напоминание после начала события"
-			case errors.Is(err, calendar.ErrReminderTimeBeforeNow):
-				output = "Нельзя добавить напоминание раньше текущего времени"
-			}
-			c.logError(err.Error())
-		} else {
-			output = "Напоминание добавлено и запущено"
-			c.logInfo(fmt.Sprintf("Добавлено напоминание к событию с ID - %s: Message - %s Before - %s",
-				ID, message, before))
+			return nil, err
 		}
+		defaults.Offsets = append(defaults.Offsets, offset)
+	}
+	return defaults, defaults.Validate()
+}
 
-	case "remove_reminder":
-		if len(parts) < 2 {
-			output = "Формат: remove_reminder \"ID события\""
-			c.logIOHistory(output)
-			return
+func countAutoReminders(event *events.Event) int {
+	count := 0
+	for _, r := range event.Reminders {
+		if r.Auto {
+			count++
 		}
-		ID := parts[1]
-		err = c.calendar.CancelEventReminder(ID)
-		if err != nil {
-			switch {
-			case errors.Is(err, calendar.ErrEventNotFound):
-				output = "Событие с введенным id не найдено"
-			case errors.Is(err, reminder.ErrNotExistReminder):
-				output = "У этого события не существует напоминания"
-			}
-			c.logError(err.Error())
-		} else {
-			output = "Напоминание удалено"
-			c.logInfo(fmt.Sprintf("Удалено напоминание у события с ID - %s", ID))
+	}
+	return count
+}
+
+// parseEventPatch принимает как старую позиционную форму (название, дата, приоритет),
+// так и именованные опции --title, --date и --priority.
+func parseEventPatch(parts []string) (events.EventPatch, error) {
+	a, err := parseArgs(parts, []string{"title", "date", "priority", "duration", "reminders"}, nil)
+	if err != nil {
+		return events.EventPatch{}, err
+	}
+	if !a.hasOptions() {
+		if len(a.positional) < 3 {
+			return events.EventPatch{}, ErrMissingArguments
 		}
-	case "help":
-		output = "Доступные команды:" +
-			"\nДобавление события: add \"название события\" \"дата и время\" \"приоритет\"" +
-			"\nРедактирование события: update \"ID события\" \"название события\" \"дата и время\" \"приоритет\"" +
-			"\nУдаление события: remove \"ID события\"" +
-			"\nДобавление напоминания: add_reminder \"ID события\" \"текст напоминания\" \"интервал до события\"" +
-			"\nУдаление напоминания: remove_reminder \"ID события\"" +
-			"\nВывести список всех событий: list" +
-			"\nВывести список всех команд: help" +
-			"\nВывести логи: log" +
-			"\nВыход из приложения: exit"
-	case "log":
-		c.showLogIOHistory()
-	case "exit":
-		err = c.calendar.Save()
+		priority := events.Priority(a.positional[2])
+		return events.EventPatch{Title: &a.positional[0], Date: &a.positional[1], Priority: &priority}, nil
+	}
+	if len(a.positional) > 0 {
+		return events.EventPatch{}, fmt.Errorf("%q: %w", a.positional[0], ErrUnexpectedArgument)
+	}
+	var patch events.EventPatch
+	if title, ok := a.option("title"); ok {
+		patch.Title = &title
+	}
+	if date, ok := a.option("date"); ok {
+		patch.Date = &date
+	}
+	if value, ok := a.option("priority"); ok {
+		priority := events.Priority(value)
+		patch.Priority = &priority
+	}
+	if value, ok := a.option("duration"); ok {
+		duration, err := timeutil.ParseDuration(value)
 		if err != nil {
-			output = "Сохранение не выполнено"
-			c.logError(err.Error())
-		} else {
-			output = "Сохранено"
-			c.logInfo("Выполнено сохранение календаря")
+			return events.EventPatch{}, fmt.Errorf("--duration: %w", calendar.ErrInvalidDuration)
 		}
-		c.logInfo("Приложение закрыто")
-		os.Exit(0)
-	default:
-		output = "Неизвестная команда. Введите 'help' для списка команд"
+		patch.Duration = &duration
 	}
-	c.logIOHistory(output)
+	if value, ok := a.option("reminders"); ok {
+		if patch.ReminderDefaults, err = parseReminderDefaults(value); err != nil {
+			return events.EventPatch{}, fmt.Errorf("--reminders: %w", err)
+		}
+	}
+	return patch, nil
+}
+
+func describeEventError(err error) string {
+	var conflictErr *calendar.ConflictError
+	switch {
+	case errors.As(err, &conflictErr):
+		return "Событие пересекается с другими событиями:\n" + formatEvents(conflictErr.Conflicts)
+	case errors.Is(err, calendar.ErrEventNotFound):
+		return "Событие с введенным id не найдено"
+	case errors.Is(err, events.ErrEmptyPatch):
+		return "Не указано ни одного поля для изменения"
+	case errors.Is(err, events.ErrInvalidPriority):
+		return fmt.Sprintf("Некорректный приоритет. Возможные приоритеты: \"%s\", \"%s\", \"%s\"",
+			events.PriorityLow, events.PriorityMedium, events.PriorityHigh)
+	case errors.Is(err, events.ErrInvalidTitle):
+		return "Некорректное название события. Длина названия от 3 до 50 символов." +
+			"\nНазвание может состоять из букв русского и английского алфавита, цифр, пробелов и точек."
+	case errors.Is(err, events.ErrInvalidDate):
+		return "Некорректный формат даты. Пример правильного формата: \"2025-10-11 15:00\""
+	case errors.Is(err, events.ErrInvalidLength):
+		return "Длительность события не может быть отрицательной"
+	case errors.Is(err, events.ErrInvalidReminderOffset):
+		return reminderDefaultsUsage
+	}
+	return "Ошибка: " + err.Error()
+}
+
+func (c *Cmd) conflictWarning(id string) string {
+	if c.calendar.ConflictPolicy() != calendar.ConflictWarn {
+		return ""
+	}
+	conflicts, err := c.calendar.EventConflicts(id)
+	if err != nil || len(conflicts) == 0 {
+		return ""
+	}
+	return "\nВнимание, событие пересекается с:\n" + formatEvents(conflicts)
+}
+
+func formatEvent(event *events.Event) string {
+	line := event.Title + " - " + event.StartAt.Format(events.DateFormat) + " - " + string(event.Priority) + " - ID: " + event.ID
+	if count := len(event.Reminders); count > 0 {
+		line += fmt.Sprintf(" - напоминаний: %d", count)
+	}
+	if event.Cancelled {
+		line += " - отменено"
+	}
+	return line
+}
+
+func describeReminderStateError(err error) string {
+	switch {
+	case errors.Is(err, reminder.ErrNotExistReminder):
+		return "Напоминание с введенным id не найдено"
+	case errors.Is(err, reminder.ErrReminderNotFired):
+		return "Напоминание еще не сработало"
+	case errors.Is(err, reminder.ErrAlreadyAcked):
+		return "Напоминание уже подтверждено"
+	case errors.Is(err, calendar.ErrInvalidDuration):
+		return "Интервал должен быть положительным"
+	}
+	return "Ошибка: " + err.Error()
+}
+
+func (c *Cmd) pending() Result {
+	pending := c.calendar.PendingReminders()
+	if len(pending) == 0 {
+		return done("Неподтвержденных напоминаний нет")
+	}
+	output := "Неподтвержденные напоминания:"
+	var list []*events.Event
+	for _, p := range pending {
+		list = append(list, p.Event)
+		output += fmt.Sprintf("\n  %s - %s: %s - ID: %s",
+			p.Reminder.At.Format(events.DateFormat), p.Event.Title, p.Event.ReminderText(p.Reminder, p.Reminder.At), p.Reminder.ID)
+	}
+	return c.eventResult(output, list...)
+}
+
+func formatMissed(missed []calendar.MissedReminder) string {
+	output := "Пропущено, пока вас не было:"
+	for _, m := range missed {
+		status := "пропущено"
+		if m.Delivered {
+			status = "отправлено с опозданием"
+		}
+		output += fmt.Sprintf("\n  %s - %s: %s (%s)",
+			m.Reminder.At.Format(events.DateFormat), m.Event.Title, m.Event.ReminderText(m.Reminder, m.Reminder.At), status)
+	}
+	return output
+}
+
+func (c *Cmd) reminders(parts []string) Result {
+	if len(parts) < 1 {
+		return failed("Формат: reminders \"ID события\"", ErrUsage)
+	}
+	event, ok := c.calendar.GetEvent()[parts[0]]
+	if !ok {
+		return failed("Событие с введенным id не найдено", calendar.ErrEventNotFound)
+	}
+	if len(event.Reminders) == 0 {
+		return c.eventResult("У события нет напоминаний", event)
+	}
+	output := "Напоминания события " + event.Title + ":"
+	for _, r := range event.Reminders {
+		output += fmt.Sprintf("\n  %s - %s - ID: %s", r.At.Format(events.DateFormat), event.ReminderText(r, r.At), r.ID)
+	}
+	return c.eventResult(output, event)
+}
+
+func formatEvents(list []*events.Event) string {
+	lines := make([]string, 0, len(list))
+	for _, event := range list {
+		lines = append(lines, "  "+formatEvent(event))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatChanges(changes []events.FieldChange) string {
+	parts := make([]string, 0, len(changes))
+	for _, change := range changes {
+		parts = append(parts, fmt.Sprintf("%s %s -> %s", change.Field, change.Old, change.New))
+	}
+	return strings.Join(parts, ", ")
 }
 
 func (c *Cmd) completer(d prompt.Document) []prompt.Suggest {
+	c.display.setInput(d)
 	suggestions := []prompt.Suggest{
 		{Text: "add", Description: "Добавить событие"},
+		{Text: "update", Description: "Изменить событие"},
 		{Text: "list", Description: "Показать все события"},
 		{Text: "remove", Description: "Удалить событие"},
+		{Text: "cancel", Description: "Отменить событие"},
+		{Text: "search", Description: "Найти события"},
+		{Text: "conflicts", Description: "Показать пересечения событий"},
+		{Text: "free", Description: "Найти свободное время"},
 		{Text: "add_reminder", Description: "Добавить напоминание"},
 		{Text: "remove_reminder", Description: "Удалить напоминание"},
+		{Text: "reminders", Description: "Показать напоминания события"},
+		{Text: "pending", Description: "Показать неподтвержденные напоминания"},
+		{Text: "ack", Description: "Подтвердить напоминание"},
+		{Text: "snooze", Description: "Отложить напоминание"},
+		{Text: "dnd", Description: "Режим \"не беспокоить\""},
+		{Text: "held", Description: "Показать задержанные напоминания"},
+		{Text: "inbox", Description: "Показать входящие напоминания"},
+		{Text: "export_timers", Description: "Экспортировать напоминания в таймеры systemd или at"},
+		{Text: "digest", Description: "Показать сводку на день"},
 		{Text: "help", Description: "Показать справку"},
 		{Text: "log", Description: "Показать логи"},
+		{Text: "json", Description: "Включить или выключить вывод в формате JSON"},
 		{Text: "exit", Description: "Выйти из программы"},
 	}
 	return prompt.FilterHasPrefix(suggestions, d.GetWordAfterCursor(), true)
 }
 
 func (c *Cmd) Run() {
+	if c.remote != nil {
+		c.runRemote()
+		return
+	}
+	c.interactive = true
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	missed, err := c.calendar.Start(ctx)
+	if err != nil {
+		c.logError(err.Error())
+	}
+	if len(missed) > 0 {
+		c.logIOHistory(formatMissed(missed))
+	}
 	p := prompt.New(
 		c.executor,
 		c.completer,
-		prompt.OptionPrefix("> "),
+		prompt.OptionPrefix(promptPrefix),
+		prompt.OptionLivePrefix(c.display.livePrefix),
 	)
 	go func() {
-		for msg := range c.calendar.Notification {
-			c.logIOHistory(msg)
-			c.logInfo(fmt.Sprintf("Пользователю выведено напоминание: %s", msg))
+		for alert := range c.calendar.Notification {
+			c.display.show(alert)
+			c.logInfo(fmt.Sprintf("Пользователю выведено напоминание: %s", alert.Text))
 		}
 	}()
 	c.logInfo("Приложение запущено")
 	p.Run()
-	close(c.calendar.Notification)
+	c.calendar.Stop()
 }
 
 func (c *Cmd) logIOHistory(log string) {
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/events"
+)
+
+func (c *Cmd) digest(parts []string) Result {
+	a, err := parseArgs(parts, nil, []string{"tomorrow"})
+	if err != nil || len(a.positional) > 0 {
+		return failed("Формат: digest [--tomorrow]", ErrUsage)
+	}
+	digest := c.calendar.Digest(c.calendar.Now(), a.flags["tomorrow"])
+	list := append(append(append([]*events.Event{}, digest.Overdue...), digest.Today...), digest.Tomorrow...)
+	return c.eventResult(digest.String(), list...)
+}
+
+func (c *Cmd) cancel(parts []string) Result {
+	if len(parts) != 1 {
+		return failed("Формат: cancel \"ID события\"", ErrUsage)
+	}
+	if err := c.calendar.CancelEvent(parts[0]); err != nil {
+		c.logError(err.Error())
+		if errors.Is(err, calendar.ErrEventCancelled) {
+			return failed("Событие уже отменено", err)
+		}
+		return failed(describeEventError(err), err)
+	}
+	c.logInfo(fmt.Sprintf("Отменено событие с ID - %s", parts[0]))
+	return c.eventResult("Событие отменено, напоминания по нему не будут отправлены", c.findEvent(parts[0]))
+}
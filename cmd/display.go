@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/c-bata/go-prompt"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/reminder"
+	"io"
+	"sync"
+	"unicode/utf8"
+)
+
+const promptPrefix = "> "
+
+const (
+	clearLine  = "\r\033[2K"
+	bell       = "\a"
+	bannerHigh = "\033[1;31m"
+	bannerLow  = "\033[1;33m"
+	resetStyle = "\033[0m"
+)
+
+// display выводит асинхронные напоминания, не портя строку ввода: строка
+// очищается, над ней печатается баннер, после чего приглашение и набранный
+// пользователем текст выводятся заново. Набранный текст запоминается
+// из completer, который go-prompt вызывает при каждом изменении ввода.
+type display struct {
+	mu    sync.Mutex
+	out   io.Writer
+	inbox func() *reminder.Inbox
+	input prompt.Document
+	bell  bool
+}
+
+func newDisplay(out io.Writer, inbox func() *reminder.Inbox) *display {
+	return &display{out: out, inbox: inbox, bell: true}
+}
+
+func (d *display) setBell(enabled bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.bell = enabled
+}
+
+func (d *display) setInput(doc prompt.Document) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.input = doc
+}
+
+func (d *display) show(alert calendar.Alert) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	banner := alert.Text
+	switch alert.Priority {
+	case "":
+	case events.PriorityHigh:
+		banner = bannerHigh + "Напоминание: " + resetStyle + banner
+		if d.bell {
+			fmt.Fprint(d.out, bell)
+		}
+	default:
+		banner = bannerLow + "Напоминание: " + resetStyle + banner
+	}
+	fmt.Fprint(d.out, clearLine+banner+"\n")
+	fmt.Fprint(d.out, d.prefix()+d.input.Text)
+	if after := utf8.RuneCountInString(d.input.TextAfterCursor()); after > 0 {
+		fmt.Fprintf(d.out, "\033[%dD", after)
+	}
+}
+
+// livePrefix показывает в приглашении число непрочитанных напоминаний.
+func (d *display) livePrefix() (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.prefix(), true
+}
+
+func (d *display) prefix() string {
+	if d.inbox == nil {
+		return promptPrefix
+	}
+	if unread := d.inbox().UnreadCount(); unread > 0 {
+		return fmt.Sprintf("[входящие: %d] %s", unread, promptPrefix)
+	}
+	return promptPrefix
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"github.com/c-bata/go-prompt"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/reminder"
+	"testing"
+	"unicode/utf8"
+)
+
+// inputDocument возвращает строку ввода text с курсором после cursor символов.
+func inputDocument(text string, cursor int) prompt.Document {
+	b := prompt.NewBuffer()
+	b.InsertText(text, false, true)
+	b.CursorLeft(utf8.RuneCountInString(text) - cursor)
+	return *b.Document()
+}
+
+func TestDisplayRedrawsInputAroundAlert(t *testing.T) {
+	var out bytes.Buffer
+	d := newDisplay(&out, nil)
+	// курсор стоит после "add ", справа от него восемь символов
+	d.setInput(inputDocument("add Планерка", 4))
+
+	d.show(calendar.Alert{Text: "Скоро планерка", Priority: events.PriorityHigh})
+	expected := bell + clearLine + bannerHigh + "Напоминание: " + resetStyle + "Скоро планерка\n" +
+		promptPrefix + "add Планерка" + "\033[8D"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	d.setInput(inputDocument("list", 4))
+	d.show(calendar.Alert{Text: "Обед", Priority: events.PriorityLow})
+	expected = clearLine + bannerLow + "Напоминание: " + resetStyle + "Обед\n" + promptPrefix + "list"
+	if out.String() != expected {
+		t.Errorf("Expected low priority banner without bell, got %q", out.String())
+	}
+
+	out.Reset()
+	d.setInput(prompt.Document{})
+	d.show(calendar.Alert{Text: "Связь с демоном восстановлена"})
+	if expected := clearLine + "Связь с демоном восстановлена\n" + promptPrefix; out.String() != expected {
+		t.Errorf("Expected plain message without banner, got %q", out.String())
+	}
+}
+
+func TestDisplayBellAndUnreadPrefix(t *testing.T) {
+	var out bytes.Buffer
+	inbox := reminder.NewInbox(nil)
+	d := newDisplay(&out, func() *reminder.Inbox { return inbox })
+	d.setBell(false)
+
+	d.show(calendar.Alert{Text: "Скоро планерка", Priority: events.PriorityHigh})
+	if bytes.Contains(out.Bytes(), []byte(bell)) {
+		t.Errorf("Expected no bell when disabled, got %q", out.String())
+	}
+
+	inbox.Record(reminder.Notification{ReminderID: "r1"}, []string{"terminal"})
+	out.Reset()
+	d.show(calendar.Alert{Text: "Обед", Priority: events.PriorityMedium})
+	if !bytes.HasSuffix(out.Bytes(), []byte("\n[входящие: 1] "+promptPrefix)) {
+		t.Errorf("Expected prompt with unread count, got %q", out.String())
+	}
+	if prefix, ok := d.livePrefix(); !ok || prefix != "[входящие: 1] "+promptPrefix {
+		t.Errorf("Expected live prefix with unread count, got %q", prefix)
+	}
+}
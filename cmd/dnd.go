@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/timeutil"
+)
+
+func (c *Cmd) doNotDisturb(parts []string) Result {
+	if len(parts) == 0 {
+		until, active := c.calendar.DoNotDisturb().ActiveUntil(c.calendar.Now())
+		if !active {
+			return done("Режим \"не беспокоить\" выключен")
+		}
+		return done("Режим \"не беспокоить\" действует до " + until.Format(events.DateFormat))
+	}
+	if parts[0] == "off" {
+		c.calendar.DisableDoNotDisturb()
+		c.logInfo("Режим \"не беспокоить\" выключен")
+		return done("Режим \"не беспокоить\" выключен")
+	}
+	d, err := timeutil.ParseDuration(parts[0])
+	if err != nil {
+		return failed("Формат: dnd [\"интервал\"|off]. Примеры интервала: \"30m\", \"2h\", \"1d\"", err)
+	}
+	until, err := c.calendar.EnableDoNotDisturb(d)
+	if err != nil {
+		c.logError(err.Error())
+		return failed("Интервал должен быть положительным", err)
+	}
+	c.logInfo("Режим \"не беспокоить\" включен до " + until.Format(events.DateFormat))
+	return done("Режим \"не беспокоить\" включен до " + until.Format(events.DateFormat))
+}
+
+func (c *Cmd) held() Result {
+	held := c.calendar.HeldNotifications()
+	if len(held) == 0 {
+		return done("Задержанных напоминаний нет")
+	}
+	output := "Задержанные напоминания:"
+	infos := make([]NotificationInfo, 0, len(held))
+	for _, n := range held {
+		output += fmt.Sprintf("\n  %s - %s (%s): %s",
+			n.FiredAt.Format(events.DateFormat), n.Title, n.Priority, n.Message)
+		infos = append(infos, notificationInfo(n))
+	}
+	result := done(output)
+	result.Notifications = infos
+	return result
+}
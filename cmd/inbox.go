@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/reminder"
+	"strings"
+)
+
+const inboxUsage = "Формат: inbox [--all] [--from \"дата\"] [--to \"дата\"]"
+
+// inbox без опций показывает непрочитанные напоминания и отмечает их
+// прочитанными, с --all - всю историю, которую можно ограничить датами.
+func (c *Cmd) inbox(parts []string) Result {
+	a, err := parseArgs(parts, []string{"from", "to"}, []string{"all"})
+	if err != nil || len(a.positional) > 0 {
+		return failed(inboxUsage, ErrUsage)
+	}
+	inbox := c.calendar.Inbox()
+	if !a.hasOptions() {
+		unread, err := inbox.Unread()
+		if err != nil {
+			c.logError(err.Error())
+		}
+		if len(unread) == 0 {
+			return done("Непрочитанных напоминаний нет")
+		}
+		return inboxResult("Непрочитанные напоминания:", unread)
+	}
+	if !a.flags["all"] {
+		return failed(inboxUsage, ErrUsage)
+	}
+	from, to, err := parseRange(a)
+	if err != nil {
+		c.logError(err.Error())
+		return failed("Некорректный формат даты. Пример правильного формата: \"2025-10-11 15:00\"", err)
+	}
+	history := inbox.History(from, to)
+	if len(history) == 0 {
+		return done("История напоминаний пуста")
+	}
+	return inboxResult("История напоминаний:", history)
+}
+
+func inboxResult(title string, entries []reminder.InboxEntry) Result {
+	result := done(title + formatInbox(entries))
+	for _, e := range entries {
+		result.Notifications = append(result.Notifications, inboxInfo(e))
+	}
+	return result
+}
+
+func formatInbox(entries []reminder.InboxEntry) string {
+	var output string
+	for _, e := range entries {
+		status := "не подтверждено"
+		if e.Acknowledged {
+			status = "подтверждено"
+		}
+		output += fmt.Sprintf("\n  %s - %s: %s [%s] - %s - ID напоминания: %s, ID события: %s",
+			e.FiredAt.Format(events.DateFormat), e.Title, e.Message, strings.Join(e.Channels, ", "),
+			status, e.ReminderID, e.EventID)
+	}
+	return output
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/events"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const listUsage = "Формат: list [today|week|month] [--from \"дата\"] [--to \"дата\"] [--priority \"high,medium\"] " +
+	"[--reminder] [--past|--upcoming] [--sort date|priority|title] [--limit N]"
+
+func (c *Cmd) list(parts []string) Result {
+	q, err := parseQuery(parts, c.calendar.Now())
+	if err != nil {
+		c.logError(err.Error())
+		switch {
+		case errors.Is(err, events.ErrInvalidDate):
+			return failed("Некорректный формат даты. Пример правильного формата: \"2025-10-11 15:00\"", err)
+		case errors.Is(err, events.ErrInvalidPriority):
+			return failed("Некорректный приоритет. Возможные приоритеты: \"low\", \"medium\", \"high\"", err)
+		}
+		return failed(listUsage, err)
+	}
+	found, err := c.calendar.Query(q)
+	if err != nil {
+		c.logError(err.Error())
+		if errors.Is(err, calendar.ErrInvalidSortKey) {
+			return failed("Некорректный ключ сортировки. Возможные значения: \"date\", \"priority\", \"title\"", err)
+		}
+		return failed(listUsage, err)
+	}
+	if len(found) == 0 {
+		return done("Список событий пуст")
+	}
+	lines := make([]string, 0, len(found))
+	for _, event := range found {
+		lines = append(lines, formatEvent(event))
+	}
+	return c.eventResult(strings.Join(lines, "\n"), found...)
+}
+
+// parseQuery разбирает аргументы list, диапазоны today, week и month
+// отсчитываются от now.
+func parseQuery(parts []string, now time.Time) (calendar.Query, error) {
+	a, err := parseArgs(parts, []string{"from", "to", "priority", "sort", "limit"},
+		[]string{"reminder", "past", "upcoming"})
+	if err != nil {
+		return calendar.Query{}, err
+	}
+	var q calendar.Query
+	if len(a.positional) > 1 {
+		return q, ErrUnexpectedArgument
+	}
+	if len(a.positional) == 1 {
+		switch strings.ToLower(a.positional[0]) {
+		case "today":
+			q.From, q.To = calendar.DayRange(now)
+		case "week":
+			q.From, q.To = calendar.WeekRange(now)
+		case "month":
+			q.From, q.To = calendar.MonthRange(now)
+		default:
+			return q, ErrUnexpectedArgument
+		}
+	}
+	from, to, err := parseRange(a)
+	if err != nil {
+		return q, err
+	}
+	if !from.IsZero() {
+		q.From = from
+	}
+	if !to.IsZero() {
+		q.To = to
+	}
+	if value, ok := a.option("priority"); ok {
+		for _, p := range strings.Split(value, ",") {
+			priority := events.Priority(strings.TrimSpace(p))
+			if err := priority.Validate(); err != nil {
+				return q, err
+			}
+			q.Priorities = append(q.Priorities, priority)
+		}
+	}
+	if a.flags["past"] && a.flags["upcoming"] {
+		return q, ErrUnexpectedArgument
+	}
+	if a.flags["past"] {
+		q.When = calendar.WhenPast
+	}
+	if a.flags["upcoming"] {
+		q.When = calendar.WhenUpcoming
+	}
+	q.HasReminder = a.flags["reminder"]
+	if value, ok := a.option("sort"); ok {
+		q.SortBy = calendar.SortKey(value)
+	}
+	if value, ok := a.option("limit"); ok {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return q, ErrUnexpectedArgument
+		}
+		q.Limit = limit
+	}
+	return q, nil
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/timeutil"
+	"testing"
+	"time"
+)
+
+func TestListRangesFollowCalendarClock(t *testing.T) {
+	clock := timeutil.NewFakeClock(time.Date(2025, 10, 11, 9, 0, 0, 0, time.Local))
+	c := NewCmd(calendar.NewCalendarWithClock(nil, clock))
+	c.Execute([]string{"add", "Планерка", "2025-10-11 12:00", "high"})
+	c.Execute([]string{"add", "Ретро", "2025-10-13 12:00", "low"})
+
+	tests := []struct {
+		period string
+		count  int
+	}{
+		{"today", 1},
+		{"week", 1},
+		{"month", 2},
+	}
+	for _, test := range tests {
+		if result := c.Execute([]string{"list", test.period}); len(result.Events) != test.count {
+			t.Errorf("%s: expected %d events by the calendar clock, got %+v", test.period, test.count, result)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/storage"
+	"github.com/google/shlex"
+	"io"
+	"strings"
+)
+
+// sessionCommands - команды, состояние которых хранится только в памяти
+// запущенного приложения. Без демона однократный запуск потерял бы его
+// сразу после выхода, поэтому такие команды выполняются только в демоне.
+var sessionCommands = map[string]bool{
+	"dnd":  true,
+	"held": true,
+}
+
+// writesFiles - команды, которые, кроме changesReminders, записывают файлы
+// при однократном запуске: inbox отмечает напоминания прочитанными. Пока
+// в том же каталоге открыто приложение, оно перезапишет эти файлы при
+// выходе, поэтому такие команды не выполняются.
+var writesFiles = map[string]bool{
+	"inbox": true,
+}
+
+// SetInstanceLock задает файл блокировки, которую держит открытое
+// приложение. Однократный запуск захватывает ее на время изменяющей
+// команды и отказывается выполнять команду, если приложение открыто.
+func (c *Cmd) SetInstanceLock(path string) {
+	c.lockPath = path
+}
+
+// RunOnce выполняет одну команду без интерактивного режима: вывод
+// успешной команды пишется в out, ошибки - в errOut, а в режиме JSON
+// любой результат пишется в out. Изменения календаря сохраняются сразу.
+// Возвращает код завершения процесса.
+func (c *Cmd) RunOnce(parts []string, out, errOut io.Writer) int {
+	result := c.runOnce(parts)
+	c.write(result, out, errOut)
+	return result.ExitCode()
+}
+
+func (c *Cmd) runOnce(parts []string) Result {
+	if c.remote != nil {
+		return c.run(parts)
+	}
+	cmd := strings.ToLower(parts[0])
+	if sessionCommands[cmd] {
+		result := failed("Команда "+cmd+" работает только в открытом приложении или через демон: запустите 'myDays daemon'", ErrDaemonRequired)
+		result.Command = cmd
+		return result
+	}
+	if (changesReminders[cmd] || writesFiles[cmd]) && c.lockPath != "" {
+		lock, err := storage.TryLock(c.lockPath)
+		if err != nil {
+			c.logError(err.Error())
+			result := failed("Ошибка: "+err.Error(), err)
+			if errors.Is(err, storage.ErrLocked) {
+				result = failed("Календарь открыт в приложении в этом каталоге: выполните команду в нем"+
+					" или запустите 'myDays daemon'", ErrInstanceRunning)
+			}
+			result.Command = cmd
+			return result
+		}
+		defer lock.Unlock()
+	}
+	result := c.run(parts)
+	if result.OK && changesReminders[result.Command] {
+		if err := c.calendar.Save(); err != nil {
+			c.logError(err.Error())
+			result = failed("Сохранение не выполнено", err)
+			result.Command = cmd
+		}
+	}
+	return result
+}
+
+// RunScript выполняет команды из r по одной на строку, пропуская пустые
+// строки и комментарии с "#". Выполнение не прерывается на ошибках,
+// код завершения - код первой неудачной команды.
+func (c *Cmd) RunScript(r io.Reader, out, errOut io.Writer) int {
+	code := ExitOK
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" || strings.HasPrefix(input, "#") {
+			continue
+		}
+		parts, err := shlex.Split(input)
+		next := ExitUsage
+		if err != nil || len(parts) == 0 {
+			c.write(failed(fmt.Sprintf("Строка %d: некорректный ввод команды", line), ErrUsage), out, errOut)
+		} else {
+			next = c.RunOnce(parts, out, errOut)
+		}
+		if code == ExitOK {
+			code = next
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		c.logError(err.Error())
+		c.write(failed("Ошибка чтения команд: "+err.Error(), err), out, errOut)
+		if code == ExitOK {
+			code = ExitFailure
+		}
+	}
+	return code
+}
+
+func (c *Cmd) write(result Result, out, errOut io.Writer) {
+	switch {
+	case c.json:
+		fmt.Fprintln(out, result.JSON())
+	case result.OK:
+		fmt.Fprintln(out, result.Message)
+	default:
+		fmt.Fprintln(errOut, result.Message)
+	}
+}
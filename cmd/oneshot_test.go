@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/storage"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRemote выполняет команды так, будто их выполнил демон.
+type fakeRemote struct {
+	executed [][]string
+}
+
+func (r *fakeRemote) Execute(parts []string) (Result, error) {
+	r.executed = append(r.executed, parts)
+	result := done("выполнено в демоне")
+	result.Command = parts[0]
+	return result, nil
+}
+
+func (r *fakeRemote) Alerts(after int64) ([]calendar.Alert, int64, error) {
+	return nil, after, nil
+}
+
+func newFileCmd(t *testing.T) (*Cmd, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "calendar.json")
+	return NewCmd(calendar.NewCalendar(storage.NewJsonStorage(path))), path
+}
+
+func TestRunScriptReadsCommandsFromStdin(t *testing.T) {
+	c, _ := newFileCmd(t)
+	date := time.Now().Add(48 * time.Hour).Format(events.DateFormat)
+	script := "# план на неделю\n\nadd Планерка \"" + date + "\" high\n  list  \n"
+	var out, errOut bytes.Buffer
+	if code := c.RunScript(strings.NewReader(script), &out, &errOut); code != ExitOK {
+		t.Errorf("Expected exit code %d, got %d (%s)", ExitOK, code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "Событие добавлено") || !strings.Contains(out.String(), "Планерка - ") {
+		t.Errorf("Expected output of add and list, got %q", out.String())
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("Expected no errors, got %q", errOut.String())
+	}
+}
+
+func TestRunScriptReturnsFirstFailingCode(t *testing.T) {
+	c, _ := newFileCmd(t)
+	script := "remove unknown\nadd Планерка завтра high\nadd \"Планерка\nhelp\n"
+	var out, errOut bytes.Buffer
+	if code := c.RunScript(strings.NewReader(script), &out, &errOut); code != ExitNotFound {
+		t.Errorf("Expected exit code of the first failure %d, got %d", ExitNotFound, code)
+	}
+	if lines := strings.Count(errOut.String(), "\n"); lines < 3 || !strings.Contains(errOut.String(), "Строка 3") {
+		t.Errorf("Expected every failure to be reported, got %q", errOut.String())
+	}
+	if out.Len() == 0 {
+		t.Error("Expected commands after failures to run")
+	}
+}
+
+func TestRunOnceSavesAfterMutatingCommand(t *testing.T) {
+	c, path := newFileCmd(t)
+	var out, errOut bytes.Buffer
+	if code := c.RunOnce([]string{"list"}, &out, &errOut); code != ExitOK {
+		t.Fatalf("Expected exit code %d, got %d", ExitOK, code)
+	}
+	if err := calendar.NewCalendar(storage.NewJsonStorage(path)).Load(); err == nil {
+		t.Error("Expected read-only command not to save the calendar")
+	}
+
+	date := time.Now().Add(48 * time.Hour).Format(events.DateFormat)
+	if code := c.RunOnce([]string{"add", "Планерка", date, "high"}, &out, &errOut); code != ExitOK {
+		t.Fatalf("Expected exit code %d, got %d (%s)", ExitOK, code, errOut.String())
+	}
+	saved := calendar.NewCalendar(storage.NewJsonStorage(path))
+	if err := saved.Load(); err != nil || len(saved.GetEvent()) != 1 {
+		t.Errorf("Expected added event to be saved, got %v (%v)", saved.GetEvent(), err)
+	}
+}
+
+func TestRunOnceRequiresDaemonForSessionCommands(t *testing.T) {
+	c, _ := newFileCmd(t)
+	c.SetJSON(true)
+	for _, parts := range [][]string{{"dnd", "2h"}, {"held"}} {
+		var out, errOut bytes.Buffer
+		if code := c.RunOnce(parts, &out, &errOut); code != ExitUnavailable {
+			t.Errorf("%v: expected exit code %d, got %d", parts, ExitUnavailable, code)
+		}
+		if !strings.Contains(out.String(), `"code":"daemon_required"`) || errOut.Len() != 0 {
+			t.Errorf("%v: expected JSON error on stdout, got %q, %q", parts, out.String(), errOut.String())
+		}
+	}
+	if _, active := c.calendar.DoNotDisturb().ActiveUntil(time.Now()); active {
+		t.Error("Expected do not disturb to stay off")
+	}
+
+	remote := &fakeRemote{}
+	client := NewRemoteCmd(remote)
+	var out, errOut bytes.Buffer
+	if code := client.RunOnce([]string{"dnd", "2h"}, &out, &errOut); code != ExitOK || len(remote.executed) != 1 {
+		t.Errorf("Expected dnd to run in the daemon, got %d, %v", code, remote.executed)
+	}
+}
+
+func TestRunOnceRefusesWritesWhileAppIsOpen(t *testing.T) {
+	c, path := newFileCmd(t)
+	lockPath := filepath.Join(filepath.Dir(path), "mydays.lock")
+	c.SetInstanceLock(lockPath)
+	lock, err := storage.TryLock(lockPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	date := time.Now().Add(48 * time.Hour).Format(events.DateFormat)
+	var out, errOut bytes.Buffer
+	if code := c.RunOnce([]string{"add", "Планерка", date, "high"}, &out, &errOut); code != ExitUnavailable {
+		t.Errorf("Expected exit code %d, got %d", ExitUnavailable, code)
+	}
+	if len(c.calendar.GetEvent()) != 0 {
+		t.Error("Expected event not to be added while the app is open")
+	}
+	if err := calendar.NewCalendar(storage.NewJsonStorage(path)).Load(); err == nil {
+		t.Error("Expected calendar not to be saved while the app is open")
+	}
+	if code := c.RunOnce([]string{"list"}, &out, &errOut); code != ExitOK {
+		t.Errorf("Expected read-only command to run, got %d", code)
+	}
+
+	lock.Unlock()
+	if code := c.RunOnce([]string{"add", "Планерка", date, "high"}, &out, &errOut); code != ExitOK {
+		t.Errorf("Expected exit code %d once the app is closed, got %d (%s)", ExitOK, code, errOut.String())
+	}
+	lock, err = storage.TryLock(lockPath)
+	if err != nil {
+		t.Fatalf("Expected lock to be released after the command, got %v", err)
+	}
+	lock.Unlock()
+}
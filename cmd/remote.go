@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/c-bata/go-prompt"
+	"github.com/elizavetanr/myDays/calendar"
+	"os"
+	"strings"
+	"time"
+)
+
+const reconnectDelay = time.Second
+
+// Remote - запущенный демон, который владеет календарем и отправляет напоминания.
+type Remote interface {
+	Execute(parts []string) (Result, error)
+	// Alerts ждет напоминаний после напоминания с номером after и возвращает
+	// номер последнего. Отрицательный after означает "только новые".
+	Alerts(after int64) ([]calendar.Alert, int64, error)
+}
+
+// NewRemoteCmd создает приложение, которое выполняет команды в демоне.
+func NewRemoteCmd(remote Remote) *Cmd {
+	return &Cmd{
+		log:     []string{},
+		display: newDisplay(os.Stdout, nil),
+		remote:  remote,
+	}
+}
+
+// run выполняет команду в демоне, если приложение подключено к нему, иначе локально.
+func (c *Cmd) run(parts []string) Result {
+	if c.remote == nil {
+		return c.Execute(parts)
+	}
+	result, err := c.remote.Execute(parts)
+	if err != nil {
+		c.logError(err.Error())
+		result = failed("Команда не выполнена, нет связи с демоном: "+err.Error()+
+			"\nЗапустите 'myDays daemon' или перезапустите приложение без демона", ErrRemoteUnavailable)
+		result.Command = strings.ToLower(parts[0])
+	}
+	return result
+}
+
+func (c *Cmd) runRemote() {
+	p := prompt.New(
+		c.executor,
+		c.completer,
+		prompt.OptionPrefix(promptPrefix),
+		prompt.OptionLivePrefix(c.display.livePrefix),
+	)
+	go c.watchAlerts()
+	c.logInfo("Приложение подключено к демону")
+	p.Run()
+}
+
+// watchAlerts выводит напоминания, которые присылает демон. Пока демон
+// недоступен, опрос повторяется, о потере и восстановлении связи
+// пользователь узнает по одному разу.
+func (c *Cmd) watchAlerts() {
+	after := int64(-1)
+	lost := false
+	for {
+		alerts, last, err := c.remote.Alerts(after)
+		if err != nil {
+			if !lost {
+				lost = true
+				c.display.show(calendar.Alert{Text: "Связь с демоном потеряна, напоминания не будут приходить до ее восстановления"})
+				c.logError(err.Error())
+			}
+			time.Sleep(reconnectDelay)
+			continue
+		}
+		if lost {
+			lost = false
+			c.display.show(calendar.Alert{Text: "Связь с демоном восстановлена"})
+			c.logInfo("Связь с демоном восстановлена")
+		}
+		for _, alert := range alerts {
+			c.display.show(alert)
+			c.logInfo(fmt.Sprintf("Пользователю выведено напоминание: %s", alert.Text))
+		}
+		after = last
+	}
+}
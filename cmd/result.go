@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/reminder"
+	"github.com/elizavetanr/myDays/timers"
+	"github.com/elizavetanr/myDays/timeutil"
+	"time"
+)
+
+var (
+	ErrUsage             = errors.New("некорректный формат команды")
+	ErrUnknownCommand    = errors.New("неизвестная команда")
+	ErrRemoteUnavailable = errors.New("нет связи с демоном")
+	ErrDaemonRequired    = errors.New("команда требует запущенного демона")
+	ErrInstanceRunning   = errors.New("календарь открыт в другом экземпляре приложения")
+)
+
+// Коды завершения процесса при однократном запуске команды.
+const (
+	ExitOK          = 0
+	ExitFailure     = 1
+	ExitUsage       = 2
+	ExitNotFound    = 3
+	ExitInvalidDate = 4
+	ExitInvalid     = 5
+	ExitConflict    = 6
+	ExitState       = 7
+	ExitStorage     = 8
+	ExitUnavailable = 9
+)
+
+// codeFailure - код ошибки, которой нет в errorCodes.
+const codeFailure = "failure"
+
+// errorCodes сопоставляет ошибкам код и код завершения процесса.
+// Ошибки проверяются по порядку через errors.Is.
+var errorCodes = []struct {
+	err  error
+	code string
+	exit int
+}{
+	{ErrUsage, "usage", ExitUsage},
+	{ErrUnknownOption, "usage", ExitUsage},
+	{ErrMissingOptionValue, "usage", ExitUsage},
+	{ErrMissingArguments, "usage", ExitUsage},
+	{ErrUnexpectedArgument, "usage", ExitUsage},
+	{ErrUnknownCommand, "unknown_command", ExitUsage},
+	{calendar.ErrEventNotFound, "event_not_found", ExitNotFound},
+	{reminder.ErrNotExistReminder, "reminder_not_found", ExitNotFound},
+	{events.ErrInvalidDate, "invalid_date", ExitInvalidDate},
+	{calendar.ErrInvalidRange, "invalid_range", ExitInvalidDate},
+	{events.ErrInvalidTitle, "invalid_title", ExitInvalid},
+	{events.ErrInvalidPriority, "invalid_priority", ExitInvalid},
+	{events.ErrInvalidLength, "invalid_length", ExitInvalid},
+	{events.ErrEmptyPatch, "empty_patch", ExitInvalid},
+	{events.ErrInvalidReminderOffset, "invalid_reminder_offset", ExitInvalid},
+	{calendar.ErrInvalidDuration, "invalid_duration", ExitInvalid},
+	{timeutil.ErrInvalidDuration, "invalid_duration", ExitInvalid},
+	{calendar.ErrInvalidSortKey, "invalid_sort_key", ExitInvalid},
+	{calendar.ErrInvalidWorkingHours, "invalid_working_hours", ExitInvalid},
+	{calendar.ErrReminderNotSpecified, "reminder_not_specified", ExitInvalid},
+	{reminder.ErrInvalidTemplate, "invalid_template", ExitInvalid},
+	{timers.ErrInvalidFormat, "invalid_timer_format", ExitInvalid},
+	{calendar.ErrEventConflict, "event_conflict", ExitConflict},
+	{calendar.ErrEventExpired, "event_expired", ExitState},
+	{calendar.ErrEventCancelled, "event_cancelled", ExitState},
+	{calendar.ErrReminderTimeAfterEvent, "reminder_after_event", ExitState},
+	{calendar.ErrReminderTimeBeforeNow, "reminder_in_past", ExitState},
+	{reminder.ErrTimeReminderIsUp, "reminder_in_past", ExitState},
+	{reminder.ErrReminderNotFired, "reminder_not_fired", ExitState},
+	{reminder.ErrAlreadyAcked, "reminder_already_acked", ExitState},
+	{calendar.ErrCalendarSaveFailed, "storage_failed", ExitStorage},
+	{calendar.ErrCalendarLoadFailed, "storage_failed", ExitStorage},
+	{reminder.ErrInboxSaveFailed, "storage_failed", ExitStorage},
+	{reminder.ErrInboxLoadFailed, "storage_failed", ExitStorage},
+	{timers.ErrExportFailed, "export_failed", ExitStorage},
+	{ErrRemoteUnavailable, "daemon_unavailable", ExitUnavailable},
+	{ErrDaemonRequired, "daemon_required", ExitUnavailable},
+	{ErrInstanceRunning, "instance_running", ExitUnavailable},
+}
+
+// Result - итог выполнения команды. В режиме --json он выводится как есть,
+// поэтому имена и смысл полей стабильны: новые поля только добавляются.
+// Message - текст для пользователя, Events - события, которых касается
+// команда, Error заполнен, только если команда не выполнена.
+type Result struct {
+	OK      bool         `json:"ok"`
+	Command string       `json:"command"`
+	Message string       `json:"message"`
+	Events  []EventInfo  `json:"events"`
+	Error   *ResultError `json:"error"`
+	// Slots - свободное время, найденное командой free.
+	Slots []SlotInfo `json:"slots"`
+	// Notifications - напоминания из входящих (inbox) или задержанные
+	// режимом тишины (held).
+	Notifications []NotificationInfo `json:"notifications"`
+}
+
+// ResultError - код ошибки из errorCodes и текст исходной ошибки.
+type ResultError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// EventInfo - событие в результате команды. Время окончания учитывает
+// длительность по умолчанию.
+type EventInfo struct {
+	ID        string         `json:"id"`
+	Title     string         `json:"title"`
+	Start     time.Time      `json:"start"`
+	End       time.Time      `json:"end"`
+	Priority  string         `json:"priority"`
+	Cancelled bool           `json:"cancelled"`
+	Reminders []ReminderInfo `json:"reminders"`
+}
+
+// ReminderInfo - напоминание события, Message - текст с подставленным шаблоном.
+// SnoozedUntil равен null, если напоминание не отложено.
+type ReminderInfo struct {
+	ID           string     `json:"id"`
+	Message      string     `json:"message"`
+	At           time.Time  `json:"at"`
+	Sent         bool       `json:"sent"`
+	Acknowledged bool       `json:"acknowledged"`
+	Missed       bool       `json:"missed"`
+	SnoozedUntil *time.Time `json:"snoozed_until"`
+}
+
+// SlotInfo - свободный интервал в результате команды free.
+type SlotInfo struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// NotificationInfo - сработавшее напоминание. Channels, Read и Acknowledged
+// заполнены только для записей входящих: задержанное напоминание еще
+// никуда не доставлено.
+type NotificationInfo struct {
+	ReminderID   string    `json:"reminder_id"`
+	EventID      string    `json:"event_id"`
+	Title        string    `json:"title"`
+	EventStart   time.Time `json:"event_start"`
+	Priority     string    `json:"priority"`
+	Message      string    `json:"message"`
+	FiredAt      time.Time `json:"fired_at"`
+	Channels     []string  `json:"channels"`
+	Read         bool      `json:"read"`
+	Acknowledged bool      `json:"acknowledged"`
+}
+
+// ExitCode возвращает код завершения процесса для результата.
+func (r Result) ExitCode() int {
+	if r.OK {
+		return ExitOK
+	}
+	for _, e := range errorCodes {
+		if r.Error != nil && e.code == r.Error.Code {
+			return e.exit
+		}
+	}
+	return ExitFailure
+}
+
+// JSON возвращает результат одной строкой JSON.
+func (r Result) JSON() string {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Sprintf(`{"ok":false,"command":%q,"message":"","events":[],"error":{"code":%q,"message":%q},"slots":[],"notifications":[]}`,
+			r.Command, codeFailure, err.Error())
+	}
+	return string(data)
+}
+
+// jsonFlag - глобальный флаг вывода в формате JSON.
+const jsonFlag = "--json"
+
+// StripJSONFlag убирает из аргументов запуска флаг --json, где бы он ни стоял,
+// и сообщает, был ли он указан.
+func StripJSONFlag(args []string) ([]string, bool) {
+	rest := make([]string, 0, len(args))
+	found := false
+	for _, arg := range args {
+		if arg == jsonFlag {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, found
+}
+
+// ErrorCode возвращает код ошибки err для вывода и передачи по сети.
+func ErrorCode(err error) string {
+	for _, e := range errorCodes {
+		if errors.Is(err, e.err) {
+			return e.code
+		}
+	}
+	return codeFailure
+}
+
+func done(message string) Result {
+	return Result{
+		OK:            true,
+		Message:       message,
+		Events:        []EventInfo{},
+		Slots:         []SlotInfo{},
+		Notifications: []NotificationInfo{},
+	}
+}
+
+func failed(message string, err error) Result {
+	return Result{
+		Message:       message,
+		Events:        []EventInfo{},
+		Error:         &ResultError{Code: ErrorCode(err), Message: err.Error()},
+		Slots:         []SlotInfo{},
+		Notifications: []NotificationInfo{},
+	}
+}
+
+// eventResult - успешный результат со списком событий. Повторяющиеся
+// события и nil пропускаются.
+func (c *Cmd) eventResult(message string, list ...*events.Event) Result {
+	result := done(message)
+	seen := make(map[string]bool)
+	for _, e := range list {
+		if e == nil || seen[e.ID] {
+			continue
+		}
+		seen[e.ID] = true
+		result.Events = append(result.Events, c.eventInfo(e))
+	}
+	return result
+}
+
+// findEvent возвращает копию события или nil, если его нет.
+func (c *Cmd) findEvent(id string) *events.Event {
+	return c.calendar.GetEvent()[id]
+}
+
+func (c *Cmd) eventInfo(e *events.Event) EventInfo {
+	info := EventInfo{
+		ID:        e.ID,
+		Title:     e.Title,
+		Start:     e.StartAt,
+		End:       e.EndAt(c.calendar.DefaultDuration()),
+		Priority:  string(e.Priority),
+		Cancelled: e.Cancelled,
+		Reminders: make([]ReminderInfo, 0, len(e.Reminders)),
+	}
+	for _, r := range e.Reminders {
+		reminderInfo := ReminderInfo{
+			ID:           r.ID,
+			Message:      e.ReminderText(r, r.At),
+			At:           r.At,
+			Sent:         r.Sent,
+			Acknowledged: r.Acknowledged,
+			Missed:       r.Missed,
+		}
+		if !r.SnoozedUntil.IsZero() {
+			snoozed := r.SnoozedUntil
+			reminderInfo.SnoozedUntil = &snoozed
+		}
+		info.Reminders = append(info.Reminders, reminderInfo)
+	}
+	return info
+}
+
+func notificationInfo(n reminder.Notification) NotificationInfo {
+	return NotificationInfo{
+		ReminderID: n.ReminderID,
+		EventID:    n.EventID,
+		Title:      n.Title,
+		EventStart: n.StartAt,
+		Priority:   n.Priority,
+		Message:    n.Message,
+		FiredAt:    n.FiredAt,
+		Channels:   []string{},
+	}
+}
+
+func inboxInfo(e reminder.InboxEntry) NotificationInfo {
+	info := notificationInfo(e.Notification)
+	info.Channels = append(info.Channels, e.Channels...)
+	info.Read = e.Read
+	info.Acknowledged = e.Acknowledged
+	return info
+}
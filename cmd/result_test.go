@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/reminder"
+	"github.com/elizavetanr/myDays/timeutil"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestResultJSONSchema фиксирует формат вывода --json. Поля можно только
+// добавлять: скрипты пользователей рассчитывают на эти имена и типы.
+func TestResultJSONSchema(t *testing.T) {
+	at := time.Date(2025, 10, 11, 9, 0, 0, 0, time.UTC)
+	snoozed := at.Add(10 * time.Minute)
+	ok := Result{
+		OK:      true,
+		Command: "reminders",
+		Message: "Напоминания события Планерка:",
+		Events: []EventInfo{{
+			ID:       "e1",
+			Title:    "Планерка",
+			Start:    at.Add(time.Hour),
+			End:      at.Add(2 * time.Hour),
+			Priority: "high",
+			Reminders: []ReminderInfo{
+				{ID: "r1", Message: "Скоро планерка", At: at, Sent: true},
+				{ID: "r2", Message: "Планерка", At: at, SnoozedUntil: &snoozed},
+			},
+		}},
+		Slots:         []SlotInfo{},
+		Notifications: []NotificationInfo{},
+	}
+	expected := `{"ok":true,"command":"reminders","message":"Напоминания события Планерка:","events":[` +
+		`{"id":"e1","title":"Планерка","start":"2025-10-11T10:00:00Z","end":"2025-10-11T11:00:00Z","priority":"high","cancelled":false,"reminders":[` +
+		`{"id":"r1","message":"Скоро планерка","at":"2025-10-11T09:00:00Z","sent":true,"acknowledged":false,"missed":false,"snoozed_until":null},` +
+		`{"id":"r2","message":"Планерка","at":"2025-10-11T09:00:00Z","sent":false,"acknowledged":false,"missed":false,"snoozed_until":"2025-10-11T09:10:00Z"}]}],` +
+		`"error":null,"slots":[],"notifications":[]}`
+	if got := ok.JSON(); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+
+	failure := failed("Событие с введенным id не найдено", calendar.ErrEventNotFound)
+	failure.Command = "remove"
+	expected = `{"ok":false,"command":"remove","message":"Событие с введенным id не найдено","events":[],` +
+		`"error":{"code":"event_not_found","message":"событие с введенным id не найдено"},"slots":[],"notifications":[]}`
+	if got := failure.JSON(); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+
+	free := done("Свободное время:")
+	free.Command = "free"
+	free.Slots = []SlotInfo{{Start: at, End: at.Add(time.Hour)}}
+	expected = `{"ok":true,"command":"free","message":"Свободное время:","events":[],"error":null,` +
+		`"slots":[{"start":"2025-10-11T09:00:00Z","end":"2025-10-11T10:00:00Z"}],"notifications":[]}`
+	if got := free.JSON(); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+
+	inbox := done("История напоминаний:")
+	inbox.Command = "inbox"
+	inbox.Notifications = []NotificationInfo{inboxInfo(reminder.InboxEntry{
+		Notification: reminder.Notification{ReminderID: "r1", EventID: "e1", Title: "Планерка",
+			StartAt: at.Add(time.Hour), Priority: "high", Message: "Скоро планерка", FiredAt: at},
+		Channels:     []string{"terminal"},
+		Acknowledged: true,
+	})}
+	expected = `{"ok":true,"command":"inbox","message":"История напоминаний:","events":[],"error":null,"slots":[],"notifications":[` +
+		`{"reminder_id":"r1","event_id":"e1","title":"Планерка","event_start":"2025-10-11T10:00:00Z","priority":"high",` +
+		`"message":"Скоро планерка","fired_at":"2025-10-11T09:00:00Z","channels":["terminal"],"read":false,"acknowledged":true}]}`
+	if got := inbox.JSON(); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestExecuteReturnsEvents(t *testing.T) {
+	c := NewCmd(calendar.NewCalendar(nil))
+	date := time.Now().Add(48 * time.Hour).Format(events.DateFormat)
+	added := c.Execute([]string{"add", "Планерка", date, "high", "--duration", "30m", "--reminders", "1h"})
+	if !added.OK || added.Command != "add" || added.Error != nil || len(added.Events) != 1 {
+		t.Fatalf("Expected added event in result, got %+v", added)
+	}
+	event := added.Events[0]
+	if event.Title != "Планерка" || event.End.Sub(event.Start) != 30*time.Minute || len(event.Reminders) != 1 {
+		t.Errorf("Expected event with duration and default reminder, got %+v", event)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(added.JSON()), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if keys := keysOf(decoded); !reflect.DeepEqual(keys, []string{"command", "error", "events", "message", "notifications", "ok", "slots"}) {
+		t.Errorf("Expected stable top-level fields, got %v", keys)
+	}
+	eventJSON := decoded["events"].([]any)[0].(map[string]any)
+	if keys := keysOf(eventJSON); !reflect.DeepEqual(keys, []string{"cancelled", "end", "id", "priority", "reminders", "start", "title"}) {
+		t.Errorf("Expected stable event fields, got %v", keys)
+	}
+
+	if listed := c.Execute([]string{"list"}); len(listed.Events) != 1 || listed.Events[0].ID != event.ID {
+		t.Errorf("Expected list to return the event, got %+v", listed.Events)
+	}
+	if help := c.Execute([]string{"help"}); !help.OK || help.Events == nil || len(help.Events) != 0 {
+		t.Errorf("Expected help without events, got %+v", help)
+	}
+}
+
+func TestExecuteReturnsSlotsAndNotifications(t *testing.T) {
+	clock := timeutil.NewFakeClock(time.Date(2025, 10, 11, 9, 0, 0, 0, time.Local))
+	c := NewCmd(calendar.NewCalendarWithClock(nil, clock))
+	added := c.Execute([]string{"add", "Планерка", "2025-10-11 12:00", "low", "--reminders", "none"})
+	c.Execute([]string{"add_reminder", added.Events[0].ID, "Скоро планерка", "1h"})
+
+	free := c.Execute([]string{"free", "2025-10-11 09:00", "2025-10-11 18:00", "1h", "--hours", "09:00-18:00"})
+	if len(free.Slots) != 2 || !free.Slots[0].End.Equal(time.Date(2025, 10, 11, 12, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected free slots around the event, got %+v", free.Slots)
+	}
+
+	c.Execute([]string{"dnd", "3h"})
+	clock.Set(time.Date(2025, 10, 11, 11, 0, 0, 0, time.Local))
+	reminderID := c.calendar.GetEvent()[added.Events[0].ID].Reminders[0].ID
+	if err := c.calendar.FireReminder(reminderID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	held := c.Execute([]string{"held"})
+	if len(held.Notifications) != 1 || held.Notifications[0].ReminderID != reminderID || len(held.Notifications[0].Channels) != 0 {
+		t.Errorf("Expected held notification, got %+v", held.Notifications)
+	}
+
+	c.calendar.Inbox().Record(reminder.Notification{ReminderID: reminderID, Title: "Планерка"}, []string{"terminal"})
+	inbox := c.Execute([]string{"inbox"})
+	if len(inbox.Notifications) != 1 || inbox.Notifications[0].ReminderID != reminderID || inbox.Notifications[0].Channels[0] != "terminal" {
+		t.Errorf("Expected inbox entry, got %+v", inbox.Notifications)
+	}
+	if list := c.Execute([]string{"list"}); list.Slots == nil || list.Notifications == nil {
+		t.Errorf("Expected empty slots and notifications for other commands, got %+v", list)
+	}
+}
+
+func TestExecuteErrorCodes(t *testing.T) {
+	c := NewCmd(calendar.NewCalendar(nil))
+	tests := []struct {
+		parts []string
+		code  string
+		exit  int
+	}{
+		{[]string{"remove", "unknown"}, "event_not_found", ExitNotFound},
+		{[]string{"add", "Планерка", "завтра", "high"}, "invalid_date", ExitInvalidDate},
+		{[]string{"add", "Планерка", "2030-10-11 10:00", "urgent"}, "invalid_priority", ExitInvalid},
+		{[]string{"add", "Планерка"}, "usage", ExitUsage},
+		{[]string{"ack", "unknown"}, "reminder_not_found", ExitNotFound},
+		{[]string{"frobnicate"}, "unknown_command", ExitUsage},
+	}
+	for _, test := range tests {
+		result := c.Execute(test.parts)
+		if result.OK || result.Error == nil || result.Error.Code != test.code || result.ExitCode() != test.exit {
+			t.Errorf("%v: expected %s with exit code %d, got %+v", test.parts, test.code, test.exit, result)
+		}
+		if result.Command != test.parts[0] || result.Message == "" || result.Error.Message == "" {
+			t.Errorf("%v: expected command and messages, got %+v", test.parts, result)
+		}
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	wrapped := fmt.Errorf("невозможно изменить событие: %w", calendar.ErrEventNotFound)
+	if code := ErrorCode(wrapped); code != "event_not_found" {
+		t.Errorf("Expected event_not_found for wrapped error, got %s", code)
+	}
+	if code := ErrorCode(&calendar.ConflictError{}); code != "event_conflict" {
+		t.Errorf("Expected event_conflict, got %s", code)
+	}
+	result := failed("Ошибка", fmt.Errorf("неожиданная ошибка"))
+	if result.Error.Code != codeFailure || result.ExitCode() != ExitFailure {
+		t.Errorf("Expected failure code for unknown error, got %+v", result)
+	}
+	if (Result{OK: true}).ExitCode() != ExitOK {
+		t.Errorf("Expected ExitOK for successful result")
+	}
+}
+
+func TestStripJSONFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		rest []string
+		json bool
+	}{
+		{[]string{"--json", "list", "today"}, []string{"list", "today"}, true},
+		{[]string{"list", "today", "--json"}, []string{"list", "today"}, true},
+		{[]string{"list", "--json", "today"}, []string{"list", "today"}, true},
+		{[]string{"list", "today"}, []string{"list", "today"}, false},
+		{[]string{"--json"}, []string{}, true},
+		{nil, []string{}, false},
+	}
+	for _, test := range tests {
+		rest, json := StripJSONFlag(test.args)
+		if !reflect.DeepEqual(rest, test.rest) || json != test.json {
+			t.Errorf("%v: expected %v, %t, got %v, %t", test.args, test.rest, test.json, rest, json)
+		}
+	}
+
+	c := NewCmd(calendar.NewCalendar(nil))
+	rest, _ := StripJSONFlag([]string{"list", "today", "--json"})
+	if result := c.Execute(rest); !result.OK || result.ExitCode() != ExitOK {
+		t.Errorf("Expected list with trailing --json to succeed, got %+v", result)
+	}
+}
+
+func keysOf(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
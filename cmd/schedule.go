@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/storage"
+	"github.com/elizavetanr/myDays/timeutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (c *Cmd) conflicts(parts []string) Result {
+	a, err := parseArgs(parts, []string{"from", "to"}, nil)
+	if err != nil || len(a.positional) > 0 {
+		return failed("Формат: conflicts [--from \"дата\"] [--to \"дата\"]", ErrUsage)
+	}
+	from, to, err := parseRange(a)
+	if err != nil {
+		c.logError(err.Error())
+		return failed("Некорректный формат даты. Пример правильного формата: \"2025-10-11 15:00\"", err)
+	}
+	conflicts := c.calendar.ConflictsInRange(from, to)
+	if len(conflicts) == 0 {
+		return done("Пересечений не найдено")
+	}
+	output := fmt.Sprintf("Найдено пересечений: %d", len(conflicts))
+	var list []*events.Event
+	for _, conflict := range conflicts {
+		output += "\n" + formatEvent(conflict.First) + "\n  пересекается с " + formatEvent(conflict.Second)
+		list = append(list, conflict.First, conflict.Second)
+	}
+	return c.eventResult(output, list...)
+}
+
+func parseRange(a *args) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+	if value, ok := a.option("from"); ok {
+		if from, err = events.ValidateDate(value); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if value, ok := a.option("to"); ok {
+		if to, err = events.ValidateDate(value); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	return from, to, nil
+}
+
+const freeUsage = "Формат: free \"с даты\" \"по дату\" \"длительность\" [--hours \"09:00-18:00\"] " +
+	"[--with \"calendar2.json,calendar3.zip\"] [--limit N]"
+
+func (c *Cmd) free(parts []string) Result {
+	a, err := parseArgs(parts, []string{"hours", "with", "limit"}, nil)
+	if err != nil || len(a.positional) != 3 {
+		return failed(freeUsage, ErrUsage)
+	}
+	from, err := events.ValidateDate(a.positional[0])
+	if err != nil {
+		return failed("Некорректный формат даты. Пример правильного формата: \"2025-10-11 15:00\"", err)
+	}
+	to, err := events.ValidateDate(a.positional[1])
+	if err != nil {
+		return failed("Некорректный формат даты. Пример правильного формата: \"2025-10-11 15:00\"", err)
+	}
+	duration, err := timeutil.ParseDuration(a.positional[2])
+	if err != nil {
+		return failed("Некорректный ввод длительности. Примеры правильного ввода: \"1h\", \"1h30m\", \"45m\"", err)
+	}
+	hours := c.calendar.WorkingHours()
+	if value, ok := a.option("hours"); ok {
+		if hours, err = calendar.ParseWorkingHours(value); err != nil {
+			return failed("Некорректный формат рабочих часов. Пример правильного формата: \"09:00-18:00\"", err)
+		}
+	}
+	limit := 0
+	if value, ok := a.option("limit"); ok {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+			return failed("Некорректное значение --limit", ErrUsage)
+		}
+	}
+
+	calendars := []*calendar.Calendar{c.calendar}
+	if value, ok := a.option("with"); ok {
+		for _, filename := range strings.Split(value, ",") {
+			other, err := c.loadCalendar(strings.TrimSpace(filename))
+			if err != nil {
+				c.logError(err.Error())
+				return failed(fmt.Sprintf("Не удалось загрузить календарь %s", filename), err)
+			}
+			calendars = append(calendars, other)
+		}
+	}
+
+	slots, err := calendar.FreeSlots(calendars, from, to, duration, hours)
+	if err != nil {
+		c.logError(err.Error())
+		if errors.Is(err, calendar.ErrInvalidRange) {
+			return failed("Дата начала интервала должна быть раньше даты окончания", err)
+		}
+		return failed("Некорректный ввод длительности. Примеры правильного ввода: \"1h\", \"1h30m\", \"45m\"", err)
+	}
+	if len(slots) == 0 {
+		return done("Свободного времени не найдено")
+	}
+	if limit > 0 && len(slots) > limit {
+		slots = slots[:limit]
+	}
+	output := "Свободное время:"
+	infos := make([]SlotInfo, 0, len(slots))
+	for _, slot := range slots {
+		output += fmt.Sprintf("\n%s - %s (%s)",
+			slot.Start.Format(events.DateFormat), slot.End.Format("15:04"), slot.Duration())
+		infos = append(infos, SlotInfo{Start: slot.Start, End: slot.End})
+	}
+	result := done(output)
+	result.Slots = infos
+	return result
+}
+
+// loadCalendar загружает дополнительный календарь только для чтения,
+// используя те же настройки длительности, что и основной.
+func (c *Cmd) loadCalendar(filename string) (*calendar.Calendar, error) {
+	var s storage.Store = storage.NewJsonStorage(filename)
+	if strings.HasSuffix(filename, ".zip") {
+		s = storage.NewZipStorage(filename)
+	}
+	other := calendar.NewCalendar(s)
+	if err := other.SetDefaultDuration(c.calendar.DefaultDuration()); err != nil {
+		return nil, err
+	}
+	if err := other.Load(); err != nil {
+		return nil, err
+	}
+	return other, nil
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/events"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	highlightStart = "\033[1;33m"
+	highlightEnd   = "\033[0m"
+)
+
+func (c *Cmd) search(parts []string) Result {
+	a, err := parseArgs(parts, []string{"limit"}, nil)
+	if err != nil || len(a.positional) == 0 {
+		return failed("Формат: search \"запрос\" [--limit N]", ErrUsage)
+	}
+	limit := 0
+	if value, ok := a.option("limit"); ok {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+			return failed("Некорректное значение --limit", ErrUsage)
+		}
+	}
+	results := c.calendar.Search(strings.Join(a.positional, " "))
+	if len(results) == 0 {
+		return done("Ничего не найдено")
+	}
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	lines := make([]string, 0, len(results))
+	found := make([]*events.Event, 0, len(results))
+	for _, result := range results {
+		lines = append(lines, formatSearchResult(result, c.interactive && !c.json))
+		found = append(found, result.Event)
+	}
+	return c.eventResult(strings.Join(lines, "\n"), found...)
+}
+
+// formatSearchResult выводит найденное событие и напоминания, в которых
+// есть совпадения. Совпадения выделяются цветом, только если colored:
+// escape-последовательности терминала не должны попадать в JSON и в вывод
+// однократного запуска, который читают скрипты.
+func formatSearchResult(result calendar.SearchResult, colored bool) string {
+	e := result.Event
+	mark := highlight
+	if !colored {
+		mark = func(text string, _ []calendar.Match) string { return text }
+	}
+	line := mark(e.Title, matchesFor(result.Matches, calendar.FieldTitle)) +
+		" - " + e.StartAt.Format(events.DateFormat) + " - " + string(e.Priority) + " - ID: " + e.ID
+	for _, group := range groupByText(matchesFor(result.Matches, calendar.FieldReminder)) {
+		line += fmt.Sprintf("\n  напоминание: %s", mark(group[0].Text, group))
+	}
+	return line
+}
+
+// groupByText разбивает совпадения по текстам, в которых они найдены:
+// слова запроса могут совпасть в разных напоминаниях одного события.
+func groupByText(matches []calendar.Match) [][]calendar.Match {
+	var groups [][]calendar.Match
+	index := make(map[string]int)
+	for _, m := range matches {
+		i, ok := index[m.Text]
+		if !ok {
+			i = len(groups)
+			index[m.Text] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], m)
+	}
+	return groups
+}
+
+func matchesFor(matches []calendar.Match, field string) []calendar.Match {
+	var found []calendar.Match
+	for _, m := range matches {
+		if m.Field == field {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// highlight выделяет найденные фрагменты текста цветом терминала,
+// объединяя пересекающиеся совпадения.
+func highlight(text string, matches []calendar.Match) string {
+	if len(matches) == 0 {
+		return text
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+	runes := []rune(text)
+	var b strings.Builder
+	pos := 0
+	for _, m := range matches {
+		start, end := max(m.Start, pos), min(m.End, len(runes))
+		if start >= end {
+			continue
+		}
+		b.WriteString(string(runes[pos:start]))
+		b.WriteString(highlightStart + string(runes[start:end]) + highlightEnd)
+		pos = end
+	}
+	b.WriteString(string(runes[pos:]))
+	return b.String()
+}
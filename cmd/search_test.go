@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/timeutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchHighlightsEachReminderSeparately(t *testing.T) {
+	clock := timeutil.NewFakeClock(time.Date(2025, 10, 11, 9, 0, 0, 0, time.Local))
+	c := NewCmd(calendar.NewCalendarWithClock(nil, clock))
+	added := c.Execute([]string{"add", "Планерка", "2025-10-12 12:00", "high"})
+	id := added.Events[0].ID
+	for _, message := range []string{"aa", "bb ccccccccc"} {
+		if result := c.Execute([]string{"add_reminder", id, message, "1h"}); !result.OK {
+			t.Fatalf("Expected reminder to be added, got %+v", result)
+		}
+	}
+
+	c.interactive = true
+	result := c.Execute([]string{"search", "aa ccccccccc"})
+	expected := "\n  напоминание: " + highlightStart + "aa" + highlightEnd +
+		"\n  напоминание: bb " + highlightStart + "ccccccccc" + highlightEnd
+	if !result.OK || !strings.HasSuffix(result.Message, expected) {
+		t.Errorf("Expected both reminders highlighted separately, got %q", result.Message)
+	}
+}
+
+func TestSearchHighlightsOnlyInteractiveText(t *testing.T) {
+	c := NewCmd(calendar.NewCalendar(nil))
+	c.Execute([]string{"add", "Планерка", "2030-10-11 10:00", "high"})
+	expected := "Планерка - 2030-10-11 10:00 - high - ID: "
+
+	if result := c.Execute([]string{"search", "планерка"}); !strings.HasPrefix(result.Message, expected) {
+		t.Errorf("Expected plain text outside the interactive mode, got %q", result.Message)
+	}
+	c.interactive = true
+	c.SetJSON(true)
+	if result := c.Execute([]string{"search", "планерка"}); !strings.HasPrefix(result.Message, expected) {
+		t.Errorf("Expected plain text in JSON, got %q", result.Message)
+	}
+	c.SetJSON(false)
+	if result := c.Execute([]string{"search", "планерка"}); !strings.Contains(result.Message, highlightStart) {
+		t.Errorf("Expected highlighted text in the interactive mode, got %q", result.Message)
+	}
+}
+
+func TestHighlightClampsMatchesToText(t *testing.T) {
+	matches := []calendar.Match{{Text: "aa", Start: 1, End: 12}}
+	if got, expected := highlight("aa", matches), "a"+highlightStart+"a"+highlightEnd; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/elizavetanr/myDays/timers"
+	"os"
+	"strings"
+)
+
+const exportTimersUsage = "Формат: export_timers [--format systemd|at] [--dir \"каталог\"] [--activate]"
+
+// changesReminders - команды, после которых внешние таймеры нужно синхронизировать.
+var changesReminders = map[string]bool{
+	"add":             true,
+	"update":          true,
+	"remove":          true,
+	"cancel":          true,
+	"add_reminder":    true,
+	"remove_reminder": true,
+	"ack":             true,
+	"snooze":          true,
+}
+
+// SetTimerExport включает синхронизацию внешних таймеров после каждого
+// изменения напоминаний.
+func (c *Cmd) SetTimerExport(e *timers.Exporter) {
+	c.timers = e
+}
+
+// NewTimerExporter создает экспорт таймеров, запускающих "myDays notify"
+// из текущего каталога, чтобы использовались те же файлы календаря.
+func NewTimerExporter(format timers.Format, dir string) (*timers.Exporter, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		var err error
+		if dir, err = timers.DefaultDir(format); err != nil {
+			return nil, err
+		}
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return timers.NewExporter(format, dir, []string{executable, "notify"}, workDir)
+}
+
+func (c *Cmd) exportTimers(parts []string) Result {
+	a, err := parseArgs(parts, []string{"format", "dir"}, []string{"activate"})
+	if err != nil || len(a.positional) > 0 {
+		return failed(exportTimersUsage, ErrUsage)
+	}
+	format := timers.FormatSystemd
+	if value, ok := a.option("format"); ok {
+		format = timers.Format(value)
+	}
+	dir, _ := a.option("dir")
+	exporter, err := NewTimerExporter(format, dir)
+	if err != nil {
+		c.logError(err.Error())
+		return failed("Некорректный формат. Возможные значения: \"systemd\", \"at\"", err)
+	}
+	exporter.Activate = a.flags["activate"]
+
+	jobs := c.timerJobs()
+	result, err := exporter.Sync(jobs)
+	if err != nil {
+		c.logError(err.Error())
+		return failed("Ошибка: "+err.Error(), err)
+	}
+	c.timers = exporter
+	c.logInfo(fmt.Sprintf("Экспортированы таймеры в %s: %d", exporter.Dir, len(jobs)))
+
+	output := fmt.Sprintf("Экспортировано напоминаний: %d, каталог: %s", len(jobs), exporter.Dir)
+	if len(result.Removed) > 0 {
+		output += fmt.Sprintf("\nУдалено устаревших файлов: %d", len(result.Removed))
+	}
+	switch {
+	case format == timers.FormatAt && len(jobs) > 0:
+		output += "\nЧтобы поставить задания в очередь, выполните:\n  " + strings.Join(exporter.AtCommands(jobs), "\n  ")
+	case format == timers.FormatSystemd && !exporter.Activate && len(jobs) > 0:
+		output += "\nЧтобы включить таймеры, выполните: systemctl --user daemon-reload && systemctl --user enable --now mydays-*.timer" +
+			"\nили повторите команду с --activate"
+	}
+	output += "\n" + syncNote(exporter)
+	return done(output)
+}
+
+// syncNote объясняет, что после изменений напоминаний обновляется само,
+// а что пользователю нужно сделать вручную.
+func syncNote(e *timers.Exporter) string {
+	switch {
+	case e.Format == timers.FormatSystemd && e.Activate:
+		return "Дальнейшие изменения напоминаний будут синхронизироваться автоматически"
+	case e.Format == timers.FormatSystemd:
+		return "После изменений напоминаний файлы таймеров будут обновляться, но systemd их не перечитает:" +
+			" выполните systemctl --user daemon-reload и включите новые таймеры или используйте --activate"
+	default:
+		return "После изменений напоминаний файлы заданий будут обновляться, но очередь at не меняется:" +
+			" повторите export_timers и выполните выведенные команды at." +
+			" Задания, уже стоящие в очереди, не удаляются (см. atq и atrm);" +
+			" задание, сработавшее раньше нового времени напоминания, ничего не отправит"
+	}
+}
+
+func (c *Cmd) timerJobs() []timers.Job {
+	upcoming := c.calendar.UpcomingReminders()
+	jobs := make([]timers.Job, 0, len(upcoming))
+	for _, p := range upcoming {
+		jobs = append(jobs, timers.Job{
+			ReminderID: p.Reminder.ID,
+			Title:      p.Event.Title,
+			Message:    p.Event.ReminderText(p.Reminder, p.Reminder.NextAt()),
+			At:         p.Reminder.NextAt(),
+		})
+	}
+	return jobs
+}
+
+func (c *Cmd) syncTimers() {
+	if c.timers == nil {
+		return
+	}
+	if _, err := c.timers.Sync(c.timerJobs()); err != nil {
+		c.logError(err.Error())
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/timeutil"
+	"os"
+	"time"
+)
+
+var (
+	ErrConfigLoadFailed = errors.New("загрузка настроек не выполнена")
+	ErrInvalidDuration  = errors.New("некорректный формат интервала в настройках")
+)
+
+// Duration хранится в файле настроек строкой вида "1h30m" или "1d".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return ErrInvalidDuration
+	}
+	parsed, err := timeutil.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("%q: %w", s, ErrInvalidDuration)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// NotifierConfig описывает дополнительный способ доставки напоминаний.
+// Type: "command" (Command, Args), "webhook" (URL), "file" (Path) или "smtp" (SMTP).
+type NotifierConfig struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Command string      `json:"command,omitempty"`
+	Args    []string    `json:"args,omitempty"`
+	URL     string      `json:"url,omitempty"`
+	Path    string      `json:"path,omitempty"`
+	SMTP    *SMTPConfig `json:"smtp,omitempty"`
+}
+
+// SMTPConfig - настройки почтового сервера. TLS: "none", "starttls" или "tls".
+type SMTPConfig struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	TLS      string   `json:"tls"`
+	Subject  string   `json:"subject"`
+	Body     string   `json:"body"`
+	Retries  int      `json:"retries"`
+	Backoff  Duration `json:"backoff"`
+}
+
+// TimersConfig включает синхронизацию внешних таймеров после каждого изменения
+// напоминаний. Format: "systemd" или "at", пустой Dir означает каталог по умолчанию.
+type TimersConfig struct {
+	Format   string `json:"format"`
+	Dir      string `json:"dir"`
+	Activate bool   `json:"activate"`
+}
+
+type Config struct {
+	ConflictPolicy  string   `json:"conflict_policy"`
+	DefaultDuration Duration `json:"default_duration"`
+	WorkingHours    string   `json:"working_hours"`
+	GraceWindow     Duration `json:"missed_grace_window"`
+	RepeatInterval  Duration `json:"high_priority_repeat_interval"`
+	// QuietHours - ежедневные тихие часы вида "22:00-07:00", пустая строка отключает их.
+	QuietHours        string `json:"quiet_hours"`
+	QuietBreakthrough bool   `json:"quiet_high_priority_breakthrough"`
+	// DefaultReminders - интервалы напоминаний, создаваемых автоматически
+	// для событий каждого приоритета, например {"high": ["1d", "1h"]}.
+	DefaultReminders map[string][]Duration `json:"default_reminders"`
+	// NotificationBell включает звуковой сигнал терминала для важных напоминаний.
+	NotificationBell bool `json:"notification_bell"`
+	// DigestTime - время ежедневной сводки вида "08:00", пустая строка отключает ее.
+	DigestTime            string        `json:"digest_time"`
+	DigestIncludeTomorrow bool          `json:"digest_include_tomorrow"`
+	Timers                *TimersConfig `json:"timers,omitempty"`
+	// Socket - путь к сокету демона, пустая строка означает путь по умолчанию.
+	Socket string `json:"socket"`
+	// Routes сопоставляет приоритету события имена способов доставки,
+	// ключ "default" задает маршрут для остальных приоритетов.
+	Notifiers []NotifierConfig    `json:"notifiers"`
+	Routes    map[string][]string `json:"routes"`
+}
+
+func Default() *Config {
+	return &Config{
+		ConflictPolicy:    "warn",
+		DefaultDuration:   Duration(time.Hour),
+		WorkingHours:      "09:00-18:00",
+		GraceWindow:       Duration(time.Hour),
+		QuietBreakthrough: true,
+		NotificationBell:  true,
+	}
+}
+
+// Load читает настройки из файла поверх значений по умолчанию.
+// Отсутствие файла не считается ошибкой.
+func Load(filename string) (*Config, error) {
+	cfg := Default()
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("%w: %w", ErrConfigLoadFailed, err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return Default(), fmt.Errorf("%w: %w", ErrConfigLoadFailed, err)
+	}
+	return cfg, nil
+}
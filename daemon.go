@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/cmd"
+	"github.com/elizavetanr/myDays/daemon"
+	"github.com/elizavetanr/myDays/logger"
+	"github.com/elizavetanr/myDays/storage"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// runDaemon запускает календарь и обслуживает клиентов через сокет, пока
+// процесс не получит SIGINT или SIGTERM. Как и открытое приложение, демон
+// держит блокировку lockPath и не запускается, если в том же каталоге уже
+// открыто приложение: иначе оба отправляли бы одни и те же напоминания
+// и перезаписывали календарь друг друга. Возвращает код завершения процесса.
+func runDaemon(c *calendar.Calendar, cli *cmd.Cmd, socket, lockPath string) int {
+	lock, err := storage.TryLock(lockPath)
+	if errors.Is(err, storage.ErrLocked) {
+		fmt.Println("Ошибка: в этом каталоге открыто приложение, закройте его перед запуском демона")
+		logger.Error("Демон не запущен: " + err.Error())
+		return 1
+	}
+	if err != nil {
+		fmt.Println("Ошибка: ", err)
+		logger.Error(err.Error())
+		return 1
+	}
+	defer lock.Unlock()
+	l, err := daemon.Listen(socket)
+	if err != nil {
+		fmt.Println("Ошибка: ", err)
+		logger.Error(err.Error())
+		return 1
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	missed, err := c.Start(ctx)
+	if err != nil {
+		l.Close()
+		fmt.Println("Ошибка: ", err)
+		logger.Error(err.Error())
+		return 1
+	}
+	if len(missed) > 0 {
+		logger.Info(fmt.Sprintf("Пропущено напоминаний, пока демон не работал: %d", len(missed)))
+	}
+	fmt.Println("Демон запущен, сокет: " + socket)
+	logger.Info("Демон запущен, сокет: " + socket)
+
+	code := 0
+	if err := daemon.NewServer(c, cli.Execute).Serve(ctx, l); err != nil {
+		fmt.Println("Ошибка: ", err)
+		logger.Error(err.Error())
+		code = 1
+	}
+	if err := c.Save(); err != nil {
+		fmt.Println("Ошибка: ", err)
+		logger.Error(err.Error())
+		code = 1
+	}
+	logger.Info("Демон остановлен")
+	return code
+}
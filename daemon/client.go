@@ -0,0 +1,125 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/cmd"
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+)
+
+// Client - подключение к демону. Если соединение разорвано, следующий
+// вызов подключается к сокету заново.
+type Client struct {
+	path string
+	mu   sync.Mutex
+	rpc  *rpc.Client
+}
+
+// Dial подключается к демону на сокете path и возвращает
+// ErrDaemonUnavailable, если демон не запущен.
+func Dial(path string) (*Client, error) {
+	c := &Client{path: path}
+	if _, err := c.conn(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Execute выполняет команду приложения в демоне и возвращает ее результат.
+func (c *Client) Execute(parts []string) (cmd.Result, error) {
+	var reply ExecuteReply
+	if err := c.call("Execute", ExecuteArgs{Parts: parts}, &reply); err != nil {
+		return cmd.Result{}, err
+	}
+	return reply.Result, nil
+}
+
+// Fire отправляет напоминание через демон. Если время напоминания еще
+// не наступило, возвращает calendar.ErrReminderNotDue.
+func (c *Client) Fire(reminderID string) error {
+	var reply FireReply
+	if err := c.call("Fire", FireArgs{ReminderID: reminderID}, &reply); err != nil {
+		return err
+	}
+	if reply.NotDue {
+		return fmt.Errorf("невозможно отправить напоминание %s: %w", reminderID, calendar.ErrReminderNotDue)
+	}
+	return nil
+}
+
+// Alerts ждет напоминаний, пришедших после напоминания с номером after,
+// и возвращает их вместе с номером последнего.
+func (c *Client) Alerts(after int64) ([]calendar.Alert, int64, error) {
+	var reply AlertsReply
+	if err := c.call("Alerts", AlertsArgs{After: after}, &reply); err != nil {
+		return nil, after, err
+	}
+	return reply.Alerts, reply.Last, nil
+}
+
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rpc == nil {
+		return nil
+	}
+	err := c.rpc.Close()
+	c.rpc = nil
+	return err
+}
+
+// call выполняет вызов и при потере соединения сбрасывает его. Вызов
+// повторяется только если он точно не был отправлен (rpc.ErrShutdown),
+// чтобы не выполнить команду дважды.
+func (c *Client) call(method string, args any, reply any) error {
+	for attempt := 0; ; attempt++ {
+		client, err := c.conn()
+		if err != nil {
+			return err
+		}
+		err = client.Call(serviceName+"."+method, args, reply)
+		if err == nil {
+			return nil
+		}
+		var serverErr rpc.ServerError
+		if errors.As(err, &serverErr) {
+			return errors.New(string(serverErr))
+		}
+		c.reset(client)
+		if errors.Is(err, rpc.ErrShutdown) && attempt == 0 {
+			continue
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) || errors.Is(err, rpc.ErrShutdown) {
+			return fmt.Errorf("%w: соединение разорвано", ErrDaemonUnavailable)
+		}
+		return fmt.Errorf("%w: %v", ErrDaemonUnavailable, err)
+	}
+}
+
+func (c *Client) conn() (*rpc.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rpc != nil {
+		return c.rpc, nil
+	}
+	conn, err := net.DialTimeout("unix", c.path, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDaemonUnavailable, err)
+	}
+	c.rpc = jsonrpc.NewClient(conn)
+	return c.rpc, nil
+}
+
+func (c *Client) reset(client *rpc.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rpc == client {
+		c.rpc.Close()
+		c.rpc = nil
+	}
+}
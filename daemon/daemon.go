@@ -0,0 +1,299 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/cmd"
+	"github.com/elizavetanr/myDays/logger"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var (
+	ErrDaemonRunning     = errors.New("демон уже запущен")
+	ErrDaemonUnavailable = errors.New("демон недоступен")
+	ErrListenFailed      = errors.New("невозможно открыть сокет демона")
+	ErrEmptyCommand      = errors.New("пустая команда")
+)
+
+// serviceName - имя, под которым методы Service доступны по RPC.
+const serviceName = "MyDays"
+
+const (
+	alertsKept  = 100
+	alertsWait  = 30 * time.Second
+	dialTimeout = time.Second
+)
+
+// DefaultSocketPath возвращает путь к сокету в XDG_RUNTIME_DIR, а если он
+// не задан - во временном каталоге с UID пользователя в имени.
+func DefaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "mydays.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("mydays-%d.sock", os.Getuid()))
+}
+
+// Listen открывает Unix-сокет демона. Сокет, оставшийся после аварийного
+// завершения, удаляется. Если на сокете уже отвечает другой демон,
+// возвращается ErrDaemonRunning.
+func Listen(path string) (net.Listener, error) {
+	if conn, err := net.DialTimeout("unix", path, dialTimeout); err == nil {
+		conn.Close()
+		return nil, ErrDaemonRunning
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %v", ErrListenFailed, err)
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrListenFailed, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("%w: %v", ErrListenFailed, err)
+	}
+	return l, nil
+}
+
+// Server выполняет команды клиентов над календарем и раздает им напоминания.
+// Команды выполняются по одной, после каждой календарь сохраняется.
+type Server struct {
+	calendar *calendar.Calendar
+	execute  func(parts []string) cmd.Result
+	mu       sync.Mutex
+	saveMu   sync.Mutex
+	alerts   *alertLog
+	wait     time.Duration
+}
+
+// NewServer создает сервер для календаря c. execute выполняет команду
+// в том же виде, в каком ее вводят в приложении, и возвращает результат.
+func NewServer(c *calendar.Calendar, execute func(parts []string) cmd.Result) *Server {
+	return &Server{
+		calendar: c,
+		execute:  execute,
+		alerts:   newAlertLog(alertsKept),
+		wait:     alertsWait,
+	}
+}
+
+// Serve принимает подключения на l до отмены ctx. Календарь должен быть
+// запущен заранее, при отмене ctx Serve дожидается выполняемых вызовов,
+// останавливает календарь и дожидается обработки последних напоминаний.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName(serviceName, &Service{server: s}); err != nil {
+		return err
+	}
+
+	consumed := make(chan struct{})
+	go func() {
+		defer close(consumed)
+		for alert := range s.calendar.Notification {
+			s.alerts.add(alert)
+			logger.Info("Клиентам демона отправлено напоминание: " + alert.Text)
+			s.save()
+		}
+	}()
+
+	var (
+		connsMu sync.Mutex
+		conns   = make(map[net.Conn]struct{})
+		served  sync.WaitGroup
+	)
+	go func() {
+		<-ctx.Done()
+		l.Close()
+		s.alerts.close()
+		connsMu.Lock()
+		for conn := range conns {
+			conn.Close()
+		}
+		connsMu.Unlock()
+	}()
+
+	var serveErr error
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				serveErr = fmt.Errorf("невозможно принять подключение: %w", err)
+			}
+			break
+		}
+		connsMu.Lock()
+		conns[conn] = struct{}{}
+		connsMu.Unlock()
+		served.Add(1)
+		go func() {
+			defer served.Done()
+			server.ServeCodec(jsonrpc.NewServerCodec(conn))
+			connsMu.Lock()
+			delete(conns, conn)
+			connsMu.Unlock()
+		}()
+	}
+	// ServeCodec возвращается, только дождавшись начатых вызовов, поэтому
+	// после Wait ни один Fire уже не отправит напоминание в закрытый канал
+	served.Wait()
+	s.calendar.Stop()
+	<-consumed
+	return serveErr
+}
+
+func (s *Server) save() {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	if err := s.calendar.Save(); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
+// Service - методы, доступные клиентам по JSON-RPC.
+type Service struct {
+	server *Server
+}
+
+type ExecuteArgs struct {
+	Parts []string
+}
+
+type ExecuteReply struct {
+	Result cmd.Result
+}
+
+// FireArgs - напоминание, которое нужно отправить немедленно.
+type FireArgs struct {
+	ReminderID string
+}
+
+// FireReply сообщает, что время напоминания еще не наступило и оно
+// не было отправлено.
+type FireReply struct {
+	NotDue bool
+}
+
+// AlertsArgs - номер последнего полученного напоминания. Отрицательное
+// значение означает, что клиенту нужны только новые напоминания.
+type AlertsArgs struct {
+	After int64
+}
+
+type AlertsReply struct {
+	Alerts []calendar.Alert
+	Last   int64
+}
+
+// Execute выполняет команду приложения и сохраняет календарь.
+func (s *Service) Execute(args ExecuteArgs, reply *ExecuteReply) error {
+	if len(args.Parts) == 0 {
+		return ErrEmptyCommand
+	}
+	s.server.mu.Lock()
+	reply.Result = s.server.execute(args.Parts)
+	s.server.mu.Unlock()
+	s.server.save()
+	return nil
+}
+
+// Fire отправляет напоминание по внешнему таймеру.
+func (s *Service) Fire(args FireArgs, reply *FireReply) error {
+	s.server.mu.Lock()
+	err := s.server.calendar.FireReminder(args.ReminderID)
+	s.server.mu.Unlock()
+	if errors.Is(err, calendar.ErrReminderNotDue) {
+		reply.NotDue = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.server.save()
+	return nil
+}
+
+// Alerts ждет напоминаний с номером больше After, но не дольше
+// нескольких секунд, и возвращает их вместе с номером последнего.
+func (s *Service) Alerts(args AlertsArgs, reply *AlertsReply) error {
+	reply.Alerts, reply.Last = s.server.alerts.since(args.After, s.server.wait)
+	return nil
+}
+
+// alertLog хранит последние напоминания, чтобы клиенты, опрашивающие
+// демон, получили их даже между запросами.
+type alertLog struct {
+	mu      sync.Mutex
+	keep    int
+	last    int64
+	alerts  []calendar.Alert
+	changed chan struct{}
+	closed  bool
+}
+
+func newAlertLog(keep int) *alertLog {
+	return &alertLog{keep: keep, changed: make(chan struct{})}
+}
+
+func (l *alertLog) add(alert calendar.Alert) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.last++
+	l.alerts = append(l.alerts, alert)
+	if len(l.alerts) > l.keep {
+		l.alerts = l.alerts[len(l.alerts)-l.keep:]
+	}
+	// после close канал уже закрыт, ожидающих клиентов нет
+	if !l.closed {
+		close(l.changed)
+		l.changed = make(chan struct{})
+	}
+}
+
+// close прерывает ожидание клиентов при остановке демона.
+func (l *alertLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.closed {
+		l.closed = true
+		close(l.changed)
+	}
+}
+
+func (l *alertLog) since(after int64, wait time.Duration) ([]calendar.Alert, int64) {
+	timeout := time.NewTimer(wait)
+	defer timeout.Stop()
+	for {
+		l.mu.Lock()
+		// номер больше последнего бывает после перезапуска демона
+		if after < 0 || after > l.last {
+			after = l.last
+		}
+		if after < l.last {
+			first := l.last - int64(len(l.alerts))
+			from := max(after-first, 0)
+			alerts := append([]calendar.Alert(nil), l.alerts[from:]...)
+			last := l.last
+			l.mu.Unlock()
+			return alerts, last
+		}
+		if l.closed {
+			l.mu.Unlock()
+			return nil, after
+		}
+		changed := l.changed
+		l.mu.Unlock()
+		select {
+		case <-changed:
+		case <-timeout.C:
+			return nil, after
+		}
+	}
+}
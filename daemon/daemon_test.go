@@ -0,0 +1,215 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/cmd"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/storage"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startServer запускает демон с календарем в temp-каталоге и возвращает
+// календарь, путь к сокету и функцию остановки.
+func startServer(t *testing.T) (*calendar.Calendar, string, context.CancelFunc) {
+	t.Helper()
+	return startServerWith(t, nil, func(parts []string) cmd.Result {
+		return cmd.Result{Message: "выполнено: " + strings.Join(parts, " "), Error: &cmd.ResultError{Code: "usage"}}
+	})
+}
+
+// startServerWith работает как startServer, но команды выполняет execute,
+// которому передается календарь демона.
+func startServerWith(t *testing.T, c *calendar.Calendar, execute func(parts []string) cmd.Result) (*calendar.Calendar, string, context.CancelFunc) {
+	t.Helper()
+	dir := t.TempDir()
+	if c == nil {
+		c = newCalendar(t)
+	}
+	socket := filepath.Join(dir, "d.sock")
+	l, err := Listen(socket)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := c.Start(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	s := NewServer(c, execute)
+	s.wait = 50 * time.Millisecond
+	done := make(chan struct{})
+	go func() {
+		s.Serve(ctx, l)
+		close(done)
+	}()
+	stop := func() {
+		cancel()
+		<-done
+	}
+	t.Cleanup(stop)
+	return c, socket, stop
+}
+
+func newCalendar(t *testing.T) *calendar.Calendar {
+	return calendar.NewCalendar(storage.NewJsonStorage(filepath.Join(t.TempDir(), "calendar.json")))
+}
+
+// dueSoon возвращает время напоминания, которое внешний таймер уже может
+// отправить, но планировщик еще не отправил.
+func dueSoon() string {
+	return time.Now().Add(30 * time.Second).Format("2006-01-02 15:04:05")
+}
+
+func TestClientExecutesCommandsInDaemon(t *testing.T) {
+	c, socket, _ := startServer(t)
+	client, err := Dial(socket)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer client.Close()
+
+	result, err := client.Execute([]string{"list", "today"})
+	if err != nil || result.Message != "выполнено: list today" || result.ExitCode() != cmd.ExitUsage {
+		t.Errorf("Expected command result, got %+v (%v)", result, err)
+	}
+	if _, err := client.Execute(nil); err == nil || !strings.Contains(err.Error(), ErrEmptyCommand.Error()) {
+		t.Errorf("Expected ErrEmptyCommand, got %v", err)
+	}
+	if err := c.Load(); err != nil {
+		t.Errorf("Expected calendar to be saved after command, got %v", err)
+	}
+}
+
+func TestClientReceivesAlertsAndFiresReminders(t *testing.T) {
+	c, socket, _ := startServer(t)
+	client, _ := Dial(socket)
+	defer client.Close()
+
+	alerts, last, err := client.Alerts(-1)
+	if err != nil || len(alerts) != 0 || last != 0 {
+		t.Fatalf("Expected no alerts yet, got %v, %d (%v)", alerts, last, err)
+	}
+	e, _ := c.AddEvent("Планерка", time.Now().Add(2*time.Hour).Format(events.DateFormat), "high", 0)
+	r, _ := c.SetEventReminder(e.ID, "Скоро планерка", dueSoon())
+	if err := client.Fire(r.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := client.Fire("unknown"); err == nil {
+		t.Errorf("Expected error for unknown reminder")
+	}
+	later, _ := c.SetEventReminder(e.ID, "Через час планерка", "1h")
+	if err := client.Fire(later.ID); !errors.Is(err, calendar.ErrReminderNotDue) {
+		t.Errorf("Expected ErrReminderNotDue, got %v", err)
+	}
+
+	alerts, last, err = client.Alerts(last)
+	if err != nil || len(alerts) != 1 || last != 1 {
+		t.Fatalf("Expected one alert, got %v, %d (%v)", alerts, last, err)
+	}
+	if alerts[0].Text != "Скоро планерка [ID: "+r.ID+"]" || alerts[0].Priority != events.PriorityHigh {
+		t.Errorf("Expected fired reminder, got %+v", alerts[0])
+	}
+	if alerts, _, _ := client.Alerts(last); len(alerts) != 0 {
+		t.Errorf("Expected no new alerts, got %v", alerts)
+	}
+}
+
+func TestClientReportsDaemonDown(t *testing.T) {
+	if _, err := Dial(filepath.Join(t.TempDir(), "none.sock")); !errors.Is(err, ErrDaemonUnavailable) {
+		t.Errorf("Expected ErrDaemonUnavailable, got %v", err)
+	}
+
+	_, socket, stop := startServer(t)
+	client, _ := Dial(socket)
+	defer client.Close()
+	stop()
+	if _, err := client.Execute([]string{"list"}); !errors.Is(err, ErrDaemonUnavailable) {
+		t.Errorf("Expected ErrDaemonUnavailable after daemon stopped, got %v", err)
+	}
+	if _, _, err := client.Alerts(0); !errors.Is(err, ErrDaemonUnavailable) {
+		t.Errorf("Expected ErrDaemonUnavailable on reconnect, got %v", err)
+	}
+}
+
+// TestServeWaitsForCallsInFlight проверяет, что календарь останавливается
+// только после завершения вызовов, начатых до остановки демона.
+func TestServeWaitsForCallsInFlight(t *testing.T) {
+	c := newCalendar(t)
+	e, _ := c.AddEvent("Планерка", time.Now().Add(2*time.Hour).Format(events.DateFormat), "high", 0)
+	r, _ := c.SetEventReminder(e.ID, "Скоро планерка", dueSoon())
+	entered, release := make(chan struct{}), make(chan struct{})
+	_, socket, stop := startServerWith(t, c, func(parts []string) cmd.Result {
+		close(entered)
+		<-release
+		if err := c.FireReminder(r.ID); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		return cmd.Result{OK: true}
+	})
+	client, _ := Dial(socket)
+	defer client.Close()
+	waiting, _ := Dial(socket)
+	defer waiting.Close()
+
+	go client.Execute([]string{"fire"})
+	go waiting.Alerts(0)
+	<-entered
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected daemon to stop after the call in flight")
+	}
+	if !c.GetEvent()[e.ID].Reminders[0].Sent {
+		t.Error("Expected reminder fired during shutdown to be sent")
+	}
+}
+
+func TestListen(t *testing.T) {
+	_, socket, stop := startServer(t)
+	if _, err := Listen(socket); !errors.Is(err, ErrDaemonRunning) {
+		t.Errorf("Expected ErrDaemonRunning, got %v", err)
+	}
+	stop()
+
+	// сокет, оставшийся после аварийного завершения
+	os.WriteFile(socket, nil, 0600)
+	l, err := Listen(socket)
+	if err != nil {
+		t.Fatalf("Expected stale socket to be replaced, got %v", err)
+	}
+	l.Close()
+}
+
+func TestAlertLogKeepsLatest(t *testing.T) {
+	log := newAlertLog(2)
+	for _, text := range []string{"a", "b", "c"} {
+		log.add(calendar.Alert{Text: text})
+	}
+	alerts, last := log.since(0, time.Millisecond)
+	if len(alerts) != 2 || alerts[0].Text != "b" || last != 3 {
+		t.Errorf("Expected two latest alerts, got %v, %d", alerts, last)
+	}
+	if alerts, last := log.since(10, time.Millisecond); len(alerts) != 0 || last != 3 {
+		t.Errorf("Expected numbering from a restarted daemon to be reset, got %v, %d", alerts, last)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		log.add(calendar.Alert{Text: "d"})
+	}()
+	if alerts, last := log.since(3, time.Second); len(alerts) != 1 || alerts[0].Text != "d" || last != 4 {
+		t.Errorf("Expected to wait for a new alert, got %v, %d", alerts, last)
+	}
+}
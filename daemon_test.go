@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/cmd"
+	"github.com/elizavetanr/myDays/storage"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRunDaemonRefusesWhileAppIsOpen проверяет, что демон не запускается
+// рядом с открытым приложением, которое уже отправляет напоминания.
+func TestRunDaemonRefusesWhileAppIsOpen(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, instanceLock)
+	socket := filepath.Join(dir, "d.sock")
+	lock, err := storage.TryLock(lockPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer lock.Unlock()
+
+	c := calendar.NewCalendar(storage.NewJsonStorage(filepath.Join(dir, "calendar.json")))
+	if code := runDaemon(c, cmd.NewCmd(c), socket, lockPath); code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("Expected daemon not to listen on the socket, got %v", err)
+	}
+}
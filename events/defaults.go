@@ -0,0 +1,42 @@
+package events
+
+import (
+	"errors"
+	"github.com/elizavetanr/myDays/timeutil"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidReminderOffset = errors.New("некорректный интервал напоминания по умолчанию")
+)
+
+// ReminderDefaults переопределяет для события напоминания по умолчанию.
+// Custom = false возвращает событие к политике, заданной для его приоритета.
+type ReminderDefaults struct {
+	Custom  bool            `json:"custom"`
+	Offsets []time.Duration `json:"offsets"`
+}
+
+func (d *ReminderDefaults) Validate() error {
+	for _, offset := range d.Offsets {
+		if offset <= 0 {
+			return ErrInvalidReminderOffset
+		}
+	}
+	return nil
+}
+
+func (d *ReminderDefaults) String() string {
+	if d == nil || !d.Custom {
+		return "по приоритету"
+	}
+	if len(d.Offsets) == 0 {
+		return "нет"
+	}
+	parts := make([]string, 0, len(d.Offsets))
+	for _, offset := range d.Offsets {
+		parts = append(parts, timeutil.FormatDuration(offset))
+	}
+	return strings.Join(parts, ",")
+}
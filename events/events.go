@@ -1,22 +1,33 @@
 package events
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/elizavetanr/myDays/reminder"
+	"github.com/elizavetanr/myDays/timeutil"
 	"github.com/google/uuid"
 	"time"
 )
 
 var (
-	ErrEmptyReminder = errors.New("пустое напоминание")
+	ErrEmptyPatch    = errors.New("не указано ни одного поля для изменения")
+	ErrInvalidLength = errors.New("некорректная длительность события")
 )
 
+const DateFormat = "2006-01-02 15:04"
+
 type Event struct {
-	ID       string             `json:"id"`
-	Title    string             `json:"title"`
-	StartAt  time.Time          `json:"date"`
-	Priority Priority           `json:"priority"`
-	Reminder *reminder.Reminder `json:"reminder"`
+	ID        string               `json:"id"`
+	Title     string               `json:"title"`
+	StartAt   time.Time            `json:"date"`
+	Priority  Priority             `json:"priority"`
+	Duration  time.Duration        `json:"duration,omitempty"`
+	Reminders []*reminder.Reminder `json:"reminders"`
+	// ReminderDefaults равен nil, если событие использует напоминания по умолчанию
+	// для своего приоритета.
+	ReminderDefaults *ReminderDefaults `json:"reminder_defaults,omitempty"`
+	Cancelled        bool              `json:"cancelled,omitempty"`
 }
 
 func NewEvent(title, date string, priority Priority) (*Event, error) {
@@ -28,45 +39,243 @@ func NewEvent(title, date string, priority Priority) (*Event, error) {
 		return nil, err
 	}
 	return &Event{
-		ID:       getNextId(),
-		Title:    title,
-		StartAt:  startAt,
-		Priority: priority,
-		Reminder: nil}, nil
+		ID:        getNextId(),
+		Title:     title,
+		StartAt:   startAt,
+		Priority:  priority,
+		Reminders: nil}, nil
 }
 
 func getNextId() string {
 	return uuid.New().String()
 }
 
+// EndAt возвращает время окончания события. Для событий без собственной
+// длительности используется defaultDuration.
+func (e *Event) EndAt(defaultDuration time.Duration) time.Time {
+	if e.Duration > 0 {
+		return e.StartAt.Add(e.Duration)
+	}
+	return e.StartAt.Add(defaultDuration)
+}
+
+// Overlaps сообщает, пересекаются ли интервалы двух событий.
+func (e *Event) Overlaps(other *Event, defaultDuration time.Duration) bool {
+	return e.StartAt.Before(other.EndAt(defaultDuration)) && other.StartAt.Before(e.EndAt(defaultDuration))
+}
+
+func ValidateDuration(d time.Duration) error {
+	if d < 0 {
+		return ErrInvalidLength
+	}
+	return nil
+}
+
+// EventPatch описывает частичное изменение события: nil-поля остаются без изменений.
+type EventPatch struct {
+	Title    *string
+	Date     *string
+	Priority *Priority
+	Duration *time.Duration
+	// ReminderDefaults с Custom = false возвращает событие к политике по умолчанию.
+	ReminderDefaults *ReminderDefaults
+}
+
+func (p EventPatch) IsEmpty() bool {
+	return p.Title == nil && p.Date == nil && p.Priority == nil && p.Duration == nil && p.ReminderDefaults == nil
+}
+
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
 func (e *Event) Update(newTitle, newDate string, priority Priority) error {
-	startAt, err := ValidateInput(newTitle, newDate)
-	if err != nil {
-		return err
+	_, err := e.Patch(EventPatch{Title: &newTitle, Date: &newDate, Priority: &priority})
+	return err
+}
+
+// Patch проверяет все переданные поля и только затем применяет их,
+// возвращая список фактически изменившихся значений.
+func (e *Event) Patch(p EventPatch) ([]FieldChange, error) {
+	if p.IsEmpty() {
+		return nil, ErrEmptyPatch
+	}
+	if p.Title != nil {
+		if err := ValidateTitle(*p.Title); err != nil {
+			return nil, err
+		}
+	}
+	var startAt time.Time
+	if p.Date != nil {
+		at, err := ValidateDate(*p.Date)
+		if err != nil {
+			return nil, err
+		}
+		startAt = at
+	}
+	if p.Priority != nil {
+		if err := p.Priority.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if p.Duration != nil {
+		if err := ValidateDuration(*p.Duration); err != nil {
+			return nil, err
+		}
+	}
+	if p.ReminderDefaults != nil {
+		if err := p.ReminderDefaults.Validate(); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := priority.Validate(); err != nil {
-		return err
+	var changes []FieldChange
+	if p.Title != nil && *p.Title != e.Title {
+		changes = append(changes, FieldChange{Field: "title", Old: e.Title, New: *p.Title})
+		e.Title = *p.Title
+	}
+	if p.Date != nil && !startAt.Equal(e.StartAt) {
+		changes = append(changes, FieldChange{Field: "date",
+			Old: e.StartAt.Format(DateFormat), New: startAt.Format(DateFormat)})
+		e.StartAt = startAt
+	}
+	if p.Priority != nil && *p.Priority != e.Priority {
+		changes = append(changes, FieldChange{Field: "priority", Old: string(e.Priority), New: string(*p.Priority)})
+		e.Priority = *p.Priority
+	}
+	if p.Duration != nil && *p.Duration != e.Duration {
+		changes = append(changes, FieldChange{Field: "duration", Old: e.Duration.String(), New: p.Duration.String()})
+		e.Duration = *p.Duration
+	}
+	if p.ReminderDefaults != nil {
+		defaults := p.ReminderDefaults
+		if !defaults.Custom {
+			defaults = nil
+		}
+		if old, updated := e.ReminderDefaults.String(), defaults.String(); old != updated {
+			changes = append(changes, FieldChange{Field: "reminders", Old: old, New: updated})
+			e.ReminderDefaults = defaults
+		}
 	}
+	return changes, nil
+}
 
-	e.Title = newTitle
-	e.StartAt = startAt
-	e.Priority = priority
+// UnmarshalJSON поддерживает старый формат файла, в котором у события
+// было единственное напоминание в поле "reminder", а смещение до начала
+// события не хранилось.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type plain Event
+	var raw struct {
+		plain
+		Reminder *reminder.Reminder `json:"reminder"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*e = Event(raw.plain)
+	if raw.Reminder != nil {
+		e.Reminders = append(e.Reminders, raw.Reminder)
+	}
+	for _, r := range e.Reminders {
+		if r.ID == "" {
+			r.ID = uuid.New().String()
+		}
+		if !r.Absolute && r.Offset == 0 {
+			r.Offset = e.StartAt.Sub(r.At)
+		}
+	}
 	return nil
 }
 
-func (e *Event) AddReminder(message string, at time.Time) error {
-	if len(message) == 0 {
-		return ErrEmptyReminder
+// AddReminder добавляет напоминание на абсолютное время. Текст может быть
+// шаблоном, пустой текст означает шаблон по умолчанию.
+func (e *Event) AddReminder(message string, at time.Time) (*reminder.Reminder, error) {
+	if err := reminder.ValidateTemplate(message); err != nil {
+		return nil, err
 	}
-	e.Reminder = reminder.NewReminder(message, at)
-	return nil
+	r := reminder.NewReminder(message, at)
+	e.Reminders = append(e.Reminders, r)
+	return r, nil
+}
+
+func (e *Event) AddReminderBefore(message string, offset time.Duration) (*reminder.Reminder, error) {
+	if err := reminder.ValidateTemplate(message); err != nil {
+		return nil, err
+	}
+	r := reminder.NewRelativeReminder(message, e.StartAt, offset)
+	e.Reminders = append(e.Reminders, r)
+	return r, nil
+}
+
+// FollowStart пересчитывает относительные напоминания под текущее время начала
+// события и возвращает те, чье время изменилось.
+func (e *Event) FollowStart() []*reminder.Reminder {
+	var moved []*reminder.Reminder
+	for _, r := range e.Reminders {
+		if r.Follow(e.StartAt) {
+			moved = append(moved, r)
+		}
+	}
+	return moved
 }
 
-func (e *Event) StartReminder(Notify func(string)) error {
-	return e.Reminder.Start(Notify)
+// TemplateData возвращает значения для шаблона текста напоминания на момент now.
+func (e *Event) TemplateData(now time.Time) reminder.TemplateData {
+	until := e.StartAt.Sub(now).Round(time.Minute)
+	if until < 0 {
+		until = 0
+	}
+	return reminder.TemplateData{
+		Title:    e.Title,
+		Priority: string(e.Priority),
+		Date:     e.StartAt.Format(DateFormat),
+		Until:    timeutil.FormatDuration(until),
+	}
+}
+
+// ReminderText возвращает текст напоминания с подставленными значениями события.
+func (e *Event) ReminderText(r *reminder.Reminder, now time.Time) string {
+	return reminder.RenderMessage(r.Message, e.TemplateData(now))
+}
+
+// Clone возвращает независимую копию события вместе с напоминаниями.
+func (e *Event) Clone() *Event {
+	clone := *e
+	clone.Reminders = make([]*reminder.Reminder, len(e.Reminders))
+	for i, r := range e.Reminders {
+		clone.Reminders[i] = r.Clone()
+	}
+	if e.ReminderDefaults != nil {
+		defaults := *e.ReminderDefaults
+		defaults.Offsets = append([]time.Duration(nil), e.ReminderDefaults.Offsets...)
+		clone.ReminderDefaults = &defaults
+	}
+	return &clone
 }
 
-func (e *Event) RemoveReminder() {
-	e.Reminder = nil
+func (e *Event) FindReminder(id string) (*reminder.Reminder, error) {
+	for _, r := range e.Reminders {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", id, reminder.ErrNotExistReminder)
+}
+
+func (e *Event) CancelReminders(s *reminder.Scheduler) {
+	for _, r := range e.Reminders {
+		r.Cancel(s)
+	}
+}
+
+func (e *Event) RemoveReminder(id string) (*reminder.Reminder, error) {
+	for i, r := range e.Reminders {
+		if r.ID == id {
+			e.Reminders = append(e.Reminders[:i], e.Reminders[i+1:]...)
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", id, reminder.ErrNotExistReminder)
 }
@@ -0,0 +1,74 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestPatchKeepsUnchangedFields(t *testing.T) {
+	e, err := NewEvent("Планерка", "2025-10-11 15:00", PriorityLow)
+	if err != nil {
+		t.Fatalf("Expected no error for new event, got %v", err)
+	}
+	date := "2025-10-11 16:00"
+	changes, err := e.Patch(EventPatch{Date: &date})
+	if err != nil {
+		t.Fatalf("Expected no error for patch, got %v", err)
+	}
+	if len(changes) != 1 || changes[0].Field != "date" {
+		t.Errorf("Expected only date change, got %v", changes)
+	}
+	if e.Title != "Планерка" || e.Priority != PriorityLow {
+		t.Errorf("Expected title and priority unchanged, got %q %q", e.Title, e.Priority)
+	}
+	if e.StartAt.Hour() != 16 {
+		t.Errorf("Expected new start hour 16, got %d", e.StartAt.Hour())
+	}
+}
+
+func TestPatchValidatesBeforeApplying(t *testing.T) {
+	e, _ := NewEvent("Планерка", "2025-10-11 15:00", PriorityLow)
+	title := "Новое название"
+	priority := Priority("urgent")
+	_, err := e.Patch(EventPatch{Title: &title, Priority: &priority})
+	if err == nil {
+		t.Error("Expected an error for priority, got none")
+	}
+	if e.Title != "Планерка" {
+		t.Errorf("Expected title unchanged after failed patch, got %q", e.Title)
+	}
+	if _, err := e.Patch(EventPatch{}); err != ErrEmptyPatch {
+		t.Errorf("Expected ErrEmptyPatch, got %v", err)
+	}
+}
+
+func TestUnmarshalLegacyReminder(t *testing.T) {
+	data := []byte(`{"id":"1","title":"Планерка","date":"2030-10-11T15:00:00Z","priority":"low",
+		"reminder":{"Message":"Скоро планерка","At":"2030-10-11T14:00:00Z","Sent":false}}`)
+	var e Event
+	if err := json.Unmarshal(data, &e); err != nil {
+		t.Fatalf("Expected no error for legacy event, got %v", err)
+	}
+	if len(e.Reminders) != 1 || e.Reminders[0].Message != "Скоро планерка" {
+		t.Fatalf("Expected legacy reminder to be migrated, got %v", e.Reminders)
+	}
+	if e.Reminders[0].ID == "" {
+		t.Error("Expected migrated reminder to get an ID")
+	}
+}
+
+func TestRemoveReminderByID(t *testing.T) {
+	e, _ := NewEvent("Планерка", "2030-10-11 15:00", PriorityLow)
+	first, _ := e.AddReminder("За день", e.StartAt.Add(-24*time.Hour))
+	second, _ := e.AddReminder("За час", e.StartAt.Add(-time.Hour))
+	if _, err := e.RemoveReminder(first.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(e.Reminders) != 1 || e.Reminders[0].ID != second.ID {
+		t.Errorf("Expected only second reminder left, got %v", e.Reminders)
+	}
+	if _, err := e.RemoveReminder(first.ID); err == nil {
+		t.Error("Expected an error for removed reminder, got none")
+	}
+}
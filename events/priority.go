@@ -22,3 +22,17 @@ func (p Priority) Validate() error {
 		return ErrInvalidPriority
 	}
 }
+
+// Rank возвращает вес приоритета: чем важнее событие, тем больше значение.
+func (p Priority) Rank() int {
+	switch p {
+	case PriorityHigh:
+		return 3
+	case PriorityMedium:
+		return 2
+	case PriorityLow:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -21,13 +21,24 @@ func IsValidTitle(title string) bool {
 	return matched
 }
 
-func ValidateInput(title, date string) (time.Time, error) {
+func ValidateTitle(title string) error {
 	if !IsValidTitle(title) {
-		return time.Time{}, ErrInvalidTitle
+		return ErrInvalidTitle
 	}
+	return nil
+}
+
+func ValidateDate(date string) (time.Time, error) {
 	at, err := dateparse.ParseLocal(date)
 	if err != nil {
 		return time.Time{}, ErrInvalidDate
 	}
-	return at, err
+	return at, nil
+}
+
+func ValidateInput(title, date string) (time.Time, error) {
+	if err := ValidateTitle(title); err != nil {
+		return time.Time{}, err
+	}
+	return ValidateDate(date)
 }
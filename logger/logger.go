@@ -1,12 +1,17 @@
 package logger
 
 import (
+	"errors"
 	"log"
 	"os"
 )
 
 const filename = "app.log"
 
+var (
+	ErrNotInitialized = errors.New("логгер не инициализирован")
+)
+
 var (
 	infoLogger  *log.Logger
 	errorLogger *log.Logger
@@ -24,10 +29,16 @@ func Init() error {
 }
 
 func Info(msg string) error {
+	if infoLogger == nil {
+		return ErrNotInitialized
+	}
 	return infoLogger.Output(2, msg)
 }
 
 func Error(msg string) error {
+	if errorLogger == nil {
+		return ErrNotInitialized
+	}
 	return errorLogger.Output(2, msg)
 }
 
@@ -1,28 +1,128 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"github.com/elizavetanr/myDays/calendar"
 	"github.com/elizavetanr/myDays/cmd"
+	"github.com/elizavetanr/myDays/config"
+	"github.com/elizavetanr/myDays/daemon"
 	"github.com/elizavetanr/myDays/logger"
+	"github.com/elizavetanr/myDays/reminder"
 	"github.com/elizavetanr/myDays/storage"
+	"github.com/elizavetanr/myDays/timers"
+	"os"
 )
 
+// instanceLock - файл блокировки, которую держит открытое приложение или
+// демон, пока его планировщик отправляет напоминания из календаря этого каталога.
+const instanceLock = "mydays.lock"
+
 //TIP <p>To run your code, right-click the code and select <b>Run</b>.</p> <p>Alternatively, click
 // the <icon src="AllIcons.Actions.Execute"/> icon in the gutter and select the <b>Run</b> menu item from here.</p>
 
 func main() {
+	cfg, err := config.Load("config.json")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка: ", err)
+	}
+	err = logger.Init()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка: ", err)
+	}
+	socket := cfg.Socket
+	if socket == "" {
+		socket = daemon.DefaultSocketPath()
+	}
+	args, jsonOutput := cmd.StripJSONFlag(os.Args[1:])
+	command := ""
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	// если демон запущен, календарем владеет он, и приложение работает как его клиент
+	var cli *cmd.Cmd
+	if command != "daemon" {
+		if client, err := daemon.Dial(socket); err == nil {
+			defer client.Close()
+			if command == "notify" {
+				os.Exit(notifyDaemon(client, args[1:]))
+			}
+			cli = cmd.NewRemoteCmd(client)
+		}
+	}
+	local := cli == nil
+	if local {
+		c := openCalendar(cfg)
+		switch command {
+		case "daemon":
+			os.Exit(runDaemon(c, newCmd(c, cfg), socket, instanceLock))
+		case "notify":
+			os.Exit(notify(c, instanceLock, args[1:]))
+		}
+		cli = newCmd(c, cfg)
+		cli.SetInstanceLock(instanceLock)
+	}
+
+	cli.SetJSON(jsonOutput)
+
+	// с аргументами или командами на stdin приложение выполняет их и завершается
+	switch {
+	case command == "-" || command == "" && !isTerminal(os.Stdin):
+		os.Exit(cli.RunScript(os.Stdin, os.Stdout, os.Stderr))
+	case command != "":
+		os.Exit(cli.RunOnce(args, os.Stdout, os.Stderr))
+	}
+	if local {
+		lock, err := storage.TryLock(instanceLock)
+		switch {
+		case errors.Is(err, storage.ErrLocked):
+			fmt.Fprintln(os.Stderr, "Ошибка: в этом каталоге уже открыто приложение или запущен демон, напоминания могут прийти дважды")
+		case err != nil:
+			fmt.Fprintln(os.Stderr, "Ошибка: ", err)
+		default:
+			defer lock.Unlock()
+		}
+		fmt.Println("Демон не запущен, напоминания будут приходить, только пока открыто приложение")
+	}
+	cli.SetBell(cfg.NotificationBell)
+	cli.Run()
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func openCalendar(cfg *config.Config) *calendar.Calendar {
 	s := storage.NewJsonStorage("calendar.json")
 	c := calendar.NewCalendar(s)
+	for _, err := range configure(c, cfg) {
+		fmt.Fprintln(os.Stderr, "Ошибка: ", err)
+	}
 
 	err := c.Load()
 	if err != nil {
-		fmt.Println("Ошибка: ", err)
+		fmt.Fprintln(os.Stderr, "Ошибка: ", err)
 	}
-	err = logger.Init()
-	if err != nil {
-		fmt.Println("Ошибка: ", err)
+	inbox := reminder.NewInbox(storage.NewJsonStorage("inbox.json"))
+	if err := inbox.Load(); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка: ", err)
 	}
+	c.SetInbox(inbox)
+	return c
+}
+
+func newCmd(c *calendar.Calendar, cfg *config.Config) *cmd.Cmd {
 	cli := cmd.NewCmd(c)
-	cli.Run()
+	if cfg.Timers != nil {
+		exporter, err := cmd.NewTimerExporter(timers.Format(cfg.Timers.Format), cfg.Timers.Dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Ошибка: ", err)
+		} else {
+			exporter.Activate = cfg.Timers.Activate
+			cli.SetTimerExport(exporter)
+		}
+	}
+	return cli
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/daemon"
+	"github.com/elizavetanr/myDays/logger"
+	"github.com/elizavetanr/myDays/storage"
+)
+
+// notify отправляет напоминание по его ID. Его вызывают внешние таймеры,
+// созданные командой export_timers, когда приложение не запущено.
+// Если в том же каталоге открыто приложение, напоминание отправит его
+// планировщик, а notify ничего не делает, чтобы не отправить напоминание
+// дважды и не перезаписать календарь открытого приложения. Таймер,
+// сработавший раньше времени напоминания (событие перенесли или
+// напоминание отложили), тоже ничего не делает.
+// Возвращает код завершения процесса.
+func notify(c *calendar.Calendar, lockPath string, args []string) int {
+	if len(args) != 1 {
+		fmt.Println("Формат: myDays notify \"ID напоминания\"")
+		return 2
+	}
+	lock, err := storage.TryLock(lockPath)
+	if errors.Is(err, storage.ErrLocked) {
+		logger.Info("Напоминание " + args[0] + " отправит открытое приложение")
+		return 0
+	}
+	if err != nil {
+		fmt.Println("Ошибка: ", err)
+		logger.Error(err.Error())
+		return 1
+	}
+	defer lock.Unlock()
+	printed := make(chan struct{})
+	go func() {
+		for alert := range c.Notification {
+			fmt.Println(alert.Text)
+		}
+		close(printed)
+	}()
+	err = c.FireReminder(args[0])
+	if closeErr := c.Notifiers().Close(); closeErr != nil {
+		logger.Error(closeErr.Error())
+	}
+	close(c.Notification)
+	<-printed
+	if errors.Is(err, calendar.ErrReminderNotDue) {
+		logger.Info("Пропущен устаревший внешний таймер: " + err.Error())
+		return 0
+	}
+	if err != nil {
+		fmt.Println("Ошибка: ", err)
+		logger.Error(err.Error())
+		return 1
+	}
+	if err := c.Save(); err != nil {
+		fmt.Println("Ошибка: ", err)
+		logger.Error(err.Error())
+		return 1
+	}
+	logger.Info("Отправлено напоминание по внешнему таймеру: " + args[0])
+	return 0
+}
+
+// notifyDaemon отправляет напоминание через запущенный демон, который
+// владеет календарем и сам выведет напоминание своим клиентам.
+func notifyDaemon(client *daemon.Client, args []string) int {
+	if len(args) != 1 {
+		fmt.Println("Формат: myDays notify \"ID напоминания\"")
+		return 2
+	}
+	err := client.Fire(args[0])
+	if errors.Is(err, calendar.ErrReminderNotDue) {
+		logger.Info("Пропущен устаревший внешний таймер: " + err.Error())
+		return 0
+	}
+	if err != nil {
+		fmt.Println("Ошибка: ", err)
+		logger.Error(err.Error())
+		return 1
+	}
+	logger.Info("Отправлено напоминание через демон по внешнему таймеру: " + args[0])
+	return 0
+}
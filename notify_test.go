@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/storage"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestNotifySkipsWhileAppIsOpen проверяет, что внешний таймер не отправляет
+// напоминание и не пишет в календарь, пока открыто приложение, которое
+// само отправит это напоминание и сохранит календарь при выходе.
+func TestNotifySkipsWhileAppIsOpen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "calendar.json")
+	lockPath := filepath.Join(dir, instanceLock)
+
+	app := calendar.NewCalendar(storage.NewJsonStorage(path))
+	e, _ := app.AddEvent("Планерка", time.Now().Add(2*time.Hour).Format(events.DateFormat), "high", 0)
+	r, _ := app.SetEventReminder(e.ID, "Скоро планерка", dueSoon())
+	if err := app.Save(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	lock, err := storage.TryLock(lockPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	timer := calendar.NewCalendar(storage.NewJsonStorage(path))
+	timer.Load()
+	if code := notify(timer, lockPath, []string{r.ID}); code != 0 {
+		t.Errorf("Expected exit code 0, got %d", code)
+	}
+	if sent := sentReminder(t, path, e.ID); sent {
+		t.Error("Expected reminder to be left to the open app")
+	}
+
+	lock.Unlock()
+	timer = calendar.NewCalendar(storage.NewJsonStorage(path))
+	timer.Load()
+	if code := notify(timer, lockPath, []string{r.ID}); code != 0 {
+		t.Errorf("Expected exit code 0, got %d", code)
+	}
+	if sent := sentReminder(t, path, e.ID); !sent {
+		t.Error("Expected reminder to be sent once the app is closed")
+	}
+}
+
+func sentReminder(t *testing.T, path, eventID string) bool {
+	t.Helper()
+	c := calendar.NewCalendar(storage.NewJsonStorage(path))
+	if err := c.Load(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return c.GetEvent()[eventID].Reminders[0].Sent
+}
+
+// dueSoon возвращает время напоминания, которое внешний таймер уже может
+// отправить, но планировщик еще не отправил.
+func dueSoon() string {
+	return time.Now().Add(30 * time.Second).Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,150 @@
+package reminder
+
+import (
+	"errors"
+	"github.com/elizavetanr/myDays/timeutil"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidQuietHours = errors.New("некорректный формат тихих часов")
+)
+
+// QuietHours - ежедневный период тишины, может переходить через полночь.
+type QuietHours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+func ParseQuietHours(s string) (QuietHours, error) {
+	start, end, err := timeutil.ParseClockRange(s)
+	if err != nil || start == end {
+		return QuietHours{}, ErrInvalidQuietHours
+	}
+	return QuietHours{Start: start, End: end}, nil
+}
+
+func (q QuietHours) String() string {
+	return timeutil.FormatClock(q.Start) + "-" + timeutil.FormatClock(q.End)
+}
+
+// endAfter возвращает момент окончания периода тишины, в который попадает now,
+// или нулевое время, если now вне периода.
+func (q QuietHours) endAfter(now time.Time) time.Time {
+	offset := timeutil.ClockOf(now)
+	switch {
+	case q.Start < q.End && offset >= q.Start && offset < q.End:
+		return timeutil.AtClock(now, q.End)
+	case q.Start > q.End && offset >= q.Start:
+		return timeutil.AtClock(timeutil.StartOfDay(now).AddDate(0, 0, 1), q.End)
+	case q.Start > q.End && offset < q.End:
+		return timeutil.AtClock(now, q.End)
+	}
+	return time.Time{}
+}
+
+// DoNotDisturb задерживает напоминания во время тихих часов и включенного
+// вручную режима "не беспокоить". Напоминания высокого приоритета проходят,
+// если разрешен Breakthrough.
+type DoNotDisturb struct {
+	mu           sync.Mutex
+	quiet        *QuietHours
+	until        time.Time
+	breakthrough bool
+	held         []Notification
+}
+
+func NewDoNotDisturb() *DoNotDisturb {
+	return &DoNotDisturb{breakthrough: true}
+}
+
+func (d *DoNotDisturb) SetQuietHours(q *QuietHours) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.quiet = q
+}
+
+func (d *DoNotDisturb) SetBreakthrough(allow bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.breakthrough = allow
+}
+
+// Enable включает режим "не беспокоить" до until.
+func (d *DoNotDisturb) Enable(until time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.until = until
+}
+
+func (d *DoNotDisturb) Disable() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.until = time.Time{}
+}
+
+// ActiveUntil возвращает время окончания тишины, если она действует в момент now.
+func (d *DoNotDisturb) ActiveUntil(now time.Time) (time.Time, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.activeUntil(now)
+}
+
+func (d *DoNotDisturb) activeUntil(now time.Time) (time.Time, bool) {
+	var end time.Time
+	if now.Before(d.until) {
+		end = d.until
+	}
+	if d.quiet != nil {
+		if quietEnd := d.quiet.endAfter(now); quietEnd.After(end) {
+			end = quietEnd
+		}
+	}
+	return end, !end.IsZero()
+}
+
+// Hold задерживает напоминание, если сейчас действует тишина, и возвращает
+// время, когда задержанные напоминания нужно будет отправить. Повтор уже
+// задержанного напоминания заменяет его, а не добавляется в очередь.
+func (d *DoNotDisturb) Hold(n Notification, now time.Time) (time.Time, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	end, active := d.activeUntil(now)
+	if !active || d.breakthrough && n.Priority == "high" {
+		return time.Time{}, false
+	}
+	for i, held := range d.held {
+		if held.ReminderID == n.ReminderID {
+			d.held[i] = n
+			return end, true
+		}
+	}
+	d.held = append(d.held, n)
+	return end, true
+}
+
+// Drop убирает из очереди задержанные напоминания с указанными ID.
+func (d *DoNotDisturb) Drop(reminderIDs ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.held = slices.DeleteFunc(d.held, func(n Notification) bool {
+		return slices.Contains(reminderIDs, n.ReminderID)
+	})
+}
+
+func (d *DoNotDisturb) Held() []Notification {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Notification(nil), d.held...)
+}
+
+// Release возвращает и очищает очередь задержанных напоминаний.
+func (d *DoNotDisturb) Release() []Notification {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	held := d.held
+	d.held = nil
+	return held
+}
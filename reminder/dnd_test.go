@@ -0,0 +1,86 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestQuietHoursAcrossMidnight(t *testing.T) {
+	d := NewDoNotDisturb()
+	quiet, err := ParseQuietHours("22:00-07:00")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	d.SetQuietHours(&quiet)
+
+	night := time.Date(2030, 10, 11, 23, 30, 0, 0, time.Local)
+	end, active := d.ActiveUntil(night)
+	if !active || !end.Equal(time.Date(2030, 10, 12, 7, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected quiet until 07:00 next day, got %v %v", end, active)
+	}
+	if _, active := d.ActiveUntil(night.Add(10 * time.Hour)); active {
+		t.Error("Expected no quiet hours at 09:30")
+	}
+}
+
+func TestQuietHoursOnDaylightSavingDay(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	d := NewDoNotDisturb()
+	quiet, _ := ParseQuietHours("22:00-07:00")
+	d.SetQuietHours(&quiet)
+
+	// 30 марта 2025 года в Берлине часы переводятся с 02:00 на 03:00
+	night := time.Date(2025, 3, 30, 5, 0, 0, 0, berlin)
+	if end, active := d.ActiveUntil(night); !active || !end.Equal(time.Date(2025, 3, 30, 7, 0, 0, 0, berlin)) {
+		t.Errorf("Expected quiet until 07:00 by the wall clock, got %v %v", end, active)
+	}
+	if _, active := d.ActiveUntil(time.Date(2025, 3, 30, 7, 30, 0, 0, berlin)); active {
+		t.Error("Expected no quiet hours at 07:30")
+	}
+}
+
+func TestHoldKeepsLowAndPassesHighPriority(t *testing.T) {
+	d := NewDoNotDisturb()
+	now := time.Now()
+	d.Enable(now.Add(time.Hour))
+
+	if _, held := d.Hold(Notification{Priority: "high"}, now); held {
+		t.Error("Expected high priority to break through")
+	}
+	if _, held := d.Hold(Notification{ReminderID: "r1", Priority: "low"}, now); !held {
+		t.Error("Expected low priority to be held")
+	}
+	d.SetBreakthrough(false)
+	if _, held := d.Hold(Notification{ReminderID: "r2", Priority: "high"}, now); !held {
+		t.Error("Expected high priority to be held without breakthrough")
+	}
+	if released := d.Release(); len(released) != 2 || len(d.Held()) != 0 {
+		t.Errorf("Expected two released notifications and empty queue, got %d", len(released))
+	}
+	d.Disable()
+	if _, held := d.Hold(Notification{Priority: "low"}, now); held {
+		t.Error("Expected no hold after disable")
+	}
+}
+
+func TestHoldKeepsOneNotificationPerReminder(t *testing.T) {
+	d := NewDoNotDisturb()
+	now := time.Now()
+	d.Enable(now.Add(time.Hour))
+
+	d.Hold(Notification{ReminderID: "r1", Message: "первый"}, now)
+	d.Hold(Notification{ReminderID: "r2", Message: "другой"}, now)
+	d.Hold(Notification{ReminderID: "r1", Message: "повтор"}, now)
+	held := d.Held()
+	if len(held) != 2 || held[0].Message != "повтор" {
+		t.Errorf("Expected repeat to replace held notification, got %v", held)
+	}
+	d.Drop("r1")
+	if held := d.Held(); len(held) != 1 || held[0].ReminderID != "r2" {
+		t.Errorf("Expected dropped notification to leave the queue, got %v", held)
+	}
+}
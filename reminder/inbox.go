@@ -0,0 +1,154 @@
+package reminder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/storage"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInboxSaveFailed = errors.New("сохранение истории напоминаний не выполнено")
+	ErrInboxLoadFailed = errors.New("загрузка истории напоминаний не выполнена")
+)
+
+// InboxEntry - доставленное напоминание. Channels - способы доставки, которыми
+// оно было доставлено, Read - показано ли оно во входящих, Acknowledged -
+// подтверждено ли само напоминание.
+type InboxEntry struct {
+	Notification
+	Channels     []string `json:"channels"`
+	Read         bool     `json:"read"`
+	Acknowledged bool     `json:"acknowledged"`
+}
+
+// Inbox хранит историю доставленных напоминаний. Без хранилища история
+// живет только в памяти.
+type Inbox struct {
+	mu      sync.Mutex
+	storage storage.Store
+	entries []InboxEntry
+}
+
+func NewInbox(s storage.Store) *Inbox {
+	return &Inbox{storage: s}
+}
+
+// Load читает историю из хранилища. Отсутствие файла не считается ошибкой.
+func (i *Inbox) Load() error {
+	if i.storage == nil {
+		return nil
+	}
+	data, err := i.storage.Load()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInboxLoadFailed, err)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if err := json.Unmarshal(data, &i.entries); err != nil {
+		return fmt.Errorf("%w: %w", ErrInboxLoadFailed, err)
+	}
+	return nil
+}
+
+// Record добавляет доставленное напоминание в историю и сохраняет ее.
+// Если то же срабатывание напоминания уже записано, например письмо
+// отправлено в фоне после вывода в терминал, к записи добавляются
+// способы доставки.
+func (i *Inbox) Record(n Notification, channels []string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for j := range i.entries {
+		e := &i.entries[j]
+		if e.ReminderID == n.ReminderID && e.FiredAt.Equal(n.FiredAt) {
+			e.Channels = append(e.Channels, channels...)
+			return i.save()
+		}
+	}
+	i.entries = append(i.entries, InboxEntry{Notification: n, Channels: channels})
+	return i.save()
+}
+
+// Unread возвращает непрочитанные напоминания и отмечает их прочитанными.
+func (i *Inbox) Unread() ([]InboxEntry, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	var unread []InboxEntry
+	for j := range i.entries {
+		if !i.entries[j].Read {
+			unread = append(unread, i.entries[j])
+			i.entries[j].Read = true
+		}
+	}
+	if len(unread) == 0 {
+		return nil, nil
+	}
+	return unread, i.save()
+}
+
+func (i *Inbox) UnreadCount() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	count := 0
+	for _, e := range i.entries {
+		if !e.Read {
+			count++
+		}
+	}
+	return count
+}
+
+// History возвращает напоминания, доставленные в интервале [from, to).
+// Нулевые границы означают отсутствие ограничения.
+func (i *Inbox) History(from, to time.Time) []InboxEntry {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	var history []InboxEntry
+	for _, e := range i.entries {
+		if !from.IsZero() && e.FiredAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !e.FiredAt.Before(to) {
+			continue
+		}
+		history = append(history, e)
+	}
+	return history
+}
+
+// Acknowledge отмечает подтвержденными все записи напоминания.
+func (i *Inbox) Acknowledge(reminderID string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	changed := false
+	for j := range i.entries {
+		if i.entries[j].ReminderID == reminderID && !i.entries[j].Acknowledged {
+			i.entries[j].Acknowledged = true
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return i.save()
+}
+
+func (i *Inbox) save() error {
+	if i.storage == nil {
+		return nil
+	}
+	data, err := json.Marshal(i.entries)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInboxSaveFailed, err)
+	}
+	if err := i.storage.Save(data); err != nil {
+		return fmt.Errorf("%w: %w", ErrInboxSaveFailed, err)
+	}
+	return nil
+}
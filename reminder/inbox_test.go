@@ -0,0 +1,57 @@
+package reminder
+
+import (
+	"github.com/elizavetanr/myDays/storage"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestInboxPersistsHistory(t *testing.T) {
+	s := storage.NewJsonStorage(filepath.Join(t.TempDir(), "inbox.json"))
+	inbox := NewInbox(s)
+	if err := inbox.Load(); err != nil {
+		t.Fatalf("Expected missing file to be ignored, got %v", err)
+	}
+	day := time.Date(2025, 10, 11, 9, 0, 0, 0, time.UTC)
+	inbox.Record(Notification{ReminderID: "r1", EventID: "e1", FiredAt: day}, []string{"terminal"})
+	inbox.Record(Notification{ReminderID: "r2", EventID: "e2", FiredAt: day.AddDate(0, 0, 1)}, []string{"terminal", "email"})
+
+	unread, err := inbox.Unread()
+	if err != nil || len(unread) != 2 {
+		t.Fatalf("Expected two unread notifications, got %v (%v)", unread, err)
+	}
+	if unread, _ := inbox.Unread(); len(unread) != 0 {
+		t.Errorf("Expected notifications to be marked read, got %v", unread)
+	}
+	inbox.Acknowledge("r2")
+
+	loaded := NewInbox(s)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	history := loaded.History(day.Add(time.Hour), time.Time{})
+	if len(history) != 1 || history[0].ReminderID != "r2" || !history[0].Read || !history[0].Acknowledged {
+		t.Errorf("Expected persisted acknowledged entry for r2, got %+v", history)
+	}
+	if len(history[0].Channels) != 2 || history[0].Channels[1] != "email" {
+		t.Errorf("Expected delivery channels to be saved, got %v", history[0].Channels)
+	}
+	if all := loaded.History(time.Time{}, time.Time{}); len(all) != 2 {
+		t.Errorf("Expected full history of two entries, got %d", len(all))
+	}
+}
+
+func TestRecordMergesChannelsOfOneFiring(t *testing.T) {
+	inbox := NewInbox(nil)
+	fired := time.Date(2025, 10, 11, 9, 0, 0, 0, time.UTC)
+	inbox.Record(Notification{ReminderID: "r1", FiredAt: fired}, []string{"email"})
+	inbox.Record(Notification{ReminderID: "r1", FiredAt: fired}, []string{"terminal"})
+	inbox.Record(Notification{ReminderID: "r1", FiredAt: fired.Add(time.Hour)}, []string{"terminal"})
+
+	history := inbox.History(time.Time{}, time.Time{})
+	if len(history) != 2 || !reflect.DeepEqual(history[0].Channels, []string{"email", "terminal"}) {
+		t.Errorf("Expected channels of one firing in one entry, got %+v", history)
+	}
+}
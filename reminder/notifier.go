@@ -0,0 +1,189 @@
+package reminder
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownNotifier   = errors.New("неизвестный способ доставки напоминаний")
+	ErrDuplicateNotifier = errors.New("способ доставки с таким именем уже зарегистрирован")
+)
+
+// Notification - данные сработавшего напоминания, передаваемые способам доставки.
+type Notification struct {
+	ReminderID string    `json:"reminder_id"`
+	EventID    string    `json:"event_id"`
+	Title      string    `json:"title"`
+	StartAt    time.Time `json:"start_at"`
+	Priority   string    `json:"priority"`
+	Message    string    `json:"message"`
+	FiredAt    time.Time `json:"fired_at"`
+}
+
+// Notifier доставляет напоминание пользователю одним из способов.
+type Notifier interface {
+	Name() string
+	Notify(n Notification) error
+}
+
+// BackgroundNotifier - способ доставки, который отправляет напоминания
+// в фоне: Notify только принимает напоминание, а об успешной доставке
+// способ сообщает позже через функцию, заданную OnDelivered.
+type BackgroundNotifier interface {
+	Notifier
+	OnDelivered(f func(n Notification))
+}
+
+// Registry хранит зарегистрированные способы доставки и правила маршрутизации:
+// для каждого приоритета события - список имен способов доставки.
+// Приоритеты без правила используют маршрут по умолчанию.
+type Registry struct {
+	mu           sync.RWMutex
+	notifiers    map[string]Notifier
+	routes       map[string][]string
+	defaultRoute []string
+	delivered    func(n Notification, name string)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		notifiers: make(map[string]Notifier),
+		routes:    make(map[string][]string),
+	}
+}
+
+func (r *Registry) Register(n Notifier) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.notifiers[n.Name()]; ok {
+		return fmt.Errorf("%s: %w", n.Name(), ErrDuplicateNotifier)
+	}
+	r.notifiers[n.Name()] = n
+	if background, ok := n.(BackgroundNotifier); ok {
+		name := n.Name()
+		background.OnDelivered(func(notification Notification) {
+			r.notifyDelivered(notification, name)
+		})
+	}
+	return nil
+}
+
+// OnDelivered задает функцию, которую вызывают способы доставки,
+// отправляющие напоминания в фоне, после успешной доставки.
+func (r *Registry) OnDelivered(f func(n Notification, name string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.delivered = f
+}
+
+func (r *Registry) notifyDelivered(n Notification, name string) {
+	r.mu.RLock()
+	f := r.delivered
+	r.mu.RUnlock()
+	if f != nil {
+		f(n, name)
+	}
+}
+
+func (r *Registry) Get(name string) (Notifier, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n, ok := r.notifiers[name]
+	return n, ok
+}
+
+func (r *Registry) Route(priority string, names ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkNames(names); err != nil {
+		return err
+	}
+	r.routes[priority] = names
+	return nil
+}
+
+func (r *Registry) SetDefaultRoute(names ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkNames(names); err != nil {
+		return err
+	}
+	r.defaultRoute = names
+	return nil
+}
+
+// Targets возвращает способы доставки для приоритета события.
+func (r *Registry) Targets(priority string) []Notifier {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names, ok := r.routes[priority]
+	if !ok {
+		names = r.defaultRoute
+	}
+	targets := make([]Notifier, 0, len(names))
+	for _, name := range names {
+		targets = append(targets, r.notifiers[name])
+	}
+	return targets
+}
+
+// Dispatch доставляет напоминание всеми способами, подходящими по приоритету.
+// Ошибка одного способа не мешает остальным.
+func (r *Registry) Dispatch(n Notification) error {
+	_, err := r.Deliver(n)
+	return err
+}
+
+// Deliver работает как Dispatch и дополнительно возвращает имена способов,
+// которыми напоминание удалось доставить. Способы, отправляющие напоминания
+// в фоне, сюда не входят: о них сообщает функция из OnDelivered.
+func (r *Registry) Deliver(n Notification) ([]string, error) {
+	var delivered []string
+	var errs []error
+	for _, target := range r.Targets(n.Priority) {
+		if err := target.Notify(n); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", target.Name(), err))
+			continue
+		}
+		if _, ok := target.(BackgroundNotifier); ok {
+			continue
+		}
+		delivered = append(delivered, target.Name())
+	}
+	return delivered, errors.Join(errs...)
+}
+
+// Close останавливает способы доставки, которые отправляют напоминания
+// в фоне, и дожидается отправки уже принятых ими напоминаний.
+func (r *Registry) Close() error {
+	// блокировка не держится во время Close: отправленные в это время
+	// напоминания сообщают о доставке через notifyDelivered
+	r.mu.RLock()
+	notifiers := make([]Notifier, 0, len(r.notifiers))
+	for _, n := range r.notifiers {
+		notifiers = append(notifiers, n)
+	}
+	r.mu.RUnlock()
+	var errs []error
+	for _, n := range notifiers {
+		if closer, ok := n.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", n.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Registry) checkNames(names []string) error {
+	for _, name := range names {
+		if _, ok := r.notifiers[name]; !ok {
+			return fmt.Errorf("%s: %w", name, ErrUnknownNotifier)
+		}
+	}
+	return nil
+}
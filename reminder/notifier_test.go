@@ -0,0 +1,72 @@
+package reminder
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegistryRoutesByPriority(t *testing.T) {
+	var terminal, urgent []string
+	r := NewRegistry()
+	r.Register(NewTerminalNotifier(func(msg, _ string) { terminal = append(terminal, msg) }))
+	r.Register(NewTerminalNotifierNamed("urgent", func(msg, _ string) { urgent = append(urgent, msg) }))
+	if err := r.Route("high", "terminal", "urgent"); err != nil {
+		t.Fatalf("Expected no error for route, got %v", err)
+	}
+	if err := r.SetDefaultRoute("terminal"); err != nil {
+		t.Fatalf("Expected no error for default route, got %v", err)
+	}
+	if err := r.Route("low", "sms"); !errors.Is(err, ErrUnknownNotifier) {
+		t.Errorf("Expected ErrUnknownNotifier, got %v", err)
+	}
+
+	r.Dispatch(Notification{Priority: "low", Message: "обычное"})
+	r.Dispatch(Notification{Priority: "high", Message: "важное"})
+	if len(terminal) != 2 || len(urgent) != 1 {
+		t.Errorf("Expected 2 terminal and 1 urgent notifications, got %v and %v", terminal, urgent)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier("hook", server.URL)
+	if err := n.Notify(Notification{EventID: "42", Message: "Скоро планерка"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if received.EventID != "42" || received.Message != "Скоро планерка" {
+		t.Errorf("Expected notification payload, got %+v", received)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if err := NewWebhookNotifier("hook", failing.URL).Notify(Notification{}); !errors.Is(err, ErrWebhookFailed) {
+		t.Errorf("Expected ErrWebhookFailed, got %v", err)
+	}
+}
+
+func TestFileNotifierAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.log")
+	n := NewFileNotifier("file", path)
+	n.Notify(Notification{Title: "Планерка", Message: "первое"})
+	n.Notify(Notification{Title: "Планерка", Message: "второе"})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 {
+		t.Errorf("Expected two lines, got %q", data)
+	}
+}
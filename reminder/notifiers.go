@@ -0,0 +1,138 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+)
+
+var (
+	ErrWebhookFailed = errors.New("вебхук вернул ошибку")
+)
+
+const hookTimeout = 10 * time.Second
+
+// TerminalNotifier выводит напоминание в терминал через функцию send,
+// которой вместе с текстом передается приоритет события.
+type TerminalNotifier struct {
+	name string
+	send func(text, priority string)
+}
+
+func NewTerminalNotifier(send func(text, priority string)) *TerminalNotifier {
+	return NewTerminalNotifierNamed("terminal", send)
+}
+
+func NewTerminalNotifierNamed(name string, send func(text, priority string)) *TerminalNotifier {
+	return &TerminalNotifier{name: name, send: send}
+}
+
+func (t *TerminalNotifier) Name() string {
+	return t.name
+}
+
+func (t *TerminalNotifier) Notify(n Notification) error {
+	t.send(fmt.Sprintf("%s [ID: %s]", n.Message, n.ReminderID), n.Priority)
+	return nil
+}
+
+// CommandNotifier запускает внешнюю команду. Данные напоминания передаются
+// в переменных окружения MYDAYS_* и в формате JSON на stdin.
+type CommandNotifier struct {
+	name    string
+	command string
+	args    []string
+}
+
+func NewCommandNotifier(name, command string, args ...string) *CommandNotifier {
+	return &CommandNotifier{name: name, command: command, args: args}
+}
+
+func (c *CommandNotifier) Name() string {
+	return c.name
+}
+
+func (c *CommandNotifier) Notify(n Notification) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, c.command, c.args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Env = append(os.Environ(),
+		"MYDAYS_REMINDER_ID="+n.ReminderID,
+		"MYDAYS_EVENT_ID="+n.EventID,
+		"MYDAYS_TITLE="+n.Title,
+		"MYDAYS_START_AT="+n.StartAt.Format(time.RFC3339),
+		"MYDAYS_PRIORITY="+n.Priority,
+		"MYDAYS_MESSAGE="+n.Message,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(output))
+	}
+	return nil
+}
+
+// WebhookNotifier отправляет напоминание POST-запросом с телом в формате JSON.
+type WebhookNotifier struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(name, url string) *WebhookNotifier {
+	return &WebhookNotifier{name: name, url: url, client: &http.Client{Timeout: hookTimeout}}
+}
+
+func (w *WebhookNotifier) Name() string {
+	return w.name
+}
+
+func (w *WebhookNotifier) Notify(n Notification) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s", ErrWebhookFailed, resp.Status)
+	}
+	return nil
+}
+
+// FileNotifier дописывает напоминания в текстовый файл, по строке на каждое.
+type FileNotifier struct {
+	name string
+	path string
+}
+
+func NewFileNotifier(name, path string) *FileNotifier {
+	return &FileNotifier{name: name, path: path}
+}
+
+func (f *FileNotifier) Name() string {
+	return f.name
+}
+
+func (f *FileNotifier) Notify(n Notification) error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\t%s\t%s\n",
+		n.FiredAt.Format(time.RFC3339), n.Priority, n.Title, n.Message, n.ReminderID)
+	return err
+}
@@ -3,27 +3,73 @@ package reminder
 import (
 	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"time"
 )
 
 var (
 	ErrTimeReminderIsUp = errors.New("время напоминания вышло")
 	ErrNotExistReminder = errors.New("напоминания не существует")
+	ErrReminderNotFired = errors.New("напоминание еще не сработало")
+	ErrAlreadyAcked     = errors.New("напоминание уже подтверждено")
 )
 
+// Reminder после срабатывания (Sent) остается ожидающим, пока его не подтвердят
+// (Acknowledged). Отложенное напоминание снова срабатывает в SnoozedUntil.
+// Напоминание с Absolute = false задано смещением Offset до начала события
+// и пересчитывается при переносе события. Auto отмечает напоминания,
+// созданные автоматически по политике напоминаний по умолчанию.
 type Reminder struct {
-	Message string
-	At      time.Time
-	Sent    bool
-	timer   *time.Timer
+	ID           string
+	Message      string
+	At           time.Time
+	Offset       time.Duration
+	Absolute     bool
+	Auto         bool
+	Sent         bool
+	Missed       bool
+	Acknowledged bool
+	SnoozedUntil time.Time
 }
 
+// NewReminder создает напоминание на абсолютное время.
 func NewReminder(message string, at time.Time) *Reminder {
 	return &Reminder{
+		ID:       uuid.New().String(),
+		Message:  message,
+		At:       at,
+		Absolute: true,
+		Sent:     false,
+	}
+}
+
+// NewRelativeReminder создает напоминание за offset до начала события start.
+func NewRelativeReminder(message string, start time.Time, offset time.Duration) *Reminder {
+	return &Reminder{
+		ID:      uuid.New().String(),
 		Message: message,
-		At:      at,
-		Sent:    false,
+		At:      start.Add(-offset),
+		Offset:  offset,
+	}
+}
+
+// Follow пересчитывает время относительного напоминания для нового начала
+// события и сбрасывает его состояние. Возвращает false для абсолютных
+// напоминаний и если время не изменилось.
+func (r *Reminder) Follow(start time.Time) bool {
+	if r.Absolute {
+		return false
+	}
+	at := start.Add(-r.Offset)
+	if at.Equal(r.At) {
+		return false
 	}
+	r.At = at
+	r.Sent = false
+	r.Missed = false
+	r.Acknowledged = false
+	r.SnoozedUntil = time.Time{}
+	return true
 }
 
 func (r *Reminder) Send(Notify func(string)) {
@@ -31,24 +77,89 @@ func (r *Reminder) Send(Notify func(string)) {
 		return
 	}
 	Notify(r.Message)
+	r.MarkSent()
+}
+
+// MarkSent отмечает напоминание отправленным, когда оно доставлено отдельно
+// от срабатывания, например после окончания тишины.
+func (r *Reminder) MarkSent() {
 	r.Sent = true
+	r.SnoozedUntil = time.Time{}
 }
 
-func (r *Reminder) Start(Notify func(string)) error {
-	duration := r.At.Sub(time.Now())
-	if duration < 0 {
-		return fmt.Errorf("невозможно запустить напоминание: %w", ErrTimeReminderIsUp)
-	} else {
-		r.timer = time.AfterFunc(duration, func() { r.Send(Notify) })
+// Repeat повторно отправляет сработавшее, но не подтвержденное напоминание.
+func (r *Reminder) Repeat(Notify func(string)) {
+	if !r.IsPending() {
+		return
+	}
+	Notify(r.Message)
+}
+
+// NextAt возвращает время ближайшего срабатывания с учетом откладывания.
+func (r *Reminder) NextAt() time.Time {
+	if !r.SnoozedUntil.IsZero() {
+		return r.SnoozedUntil
+	}
+	return r.At
+}
+
+// Schedule ставит напоминание в очередь планировщика, fire вызывается в момент
+// срабатывания. Уже отправленные напоминания не планируются повторно.
+func (r *Reminder) Schedule(s *Scheduler, fire func()) error {
+	if r.Sent || r.Missed {
 		return nil
 	}
+	if r.NextAt().Before(s.Now()) {
+		return fmt.Errorf("невозможно запустить напоминание: %w", ErrTimeReminderIsUp)
+	}
+	s.Schedule(r.ID, r.NextAt(), fire)
+	return nil
+}
+
+// Clone возвращает копию напоминания, не связанную с исходным.
+func (r *Reminder) Clone() *Reminder {
+	clone := *r
+	return &clone
+}
+
+func (r *Reminder) IsPending() bool {
+	return r.Sent && !r.Acknowledged
+}
+
+func (r *Reminder) Acknowledge() error {
+	if r.Acknowledged {
+		return ErrAlreadyAcked
+	}
+	if !r.Sent {
+		return ErrReminderNotFired
+	}
+	r.Acknowledged = true
+	return nil
+}
+
+// Snooze откладывает сработавшее напоминание до until.
+func (r *Reminder) Snooze(until time.Time) error {
+	if r.Acknowledged {
+		return ErrAlreadyAcked
+	}
+	if !r.Sent {
+		return ErrReminderNotFired
+	}
+	r.Sent = false
+	r.SnoozedUntil = until
+	return nil
+}
+
+// IsOverdue сообщает, что время напоминания прошло, а оно так и не было отправлено.
+func (r *Reminder) IsOverdue(now time.Time) bool {
+	return !r.Sent && !r.Missed && r.NextAt().Before(now)
 }
 
-func (r *Reminder) Stop() error {
+func (r *Reminder) Cancel(s *Scheduler) error {
 	if !r.isExist() {
 		return fmt.Errorf("невозможно остановить напоминание: %w", ErrNotExistReminder)
 	}
-	r.timer.Stop()
+	s.Cancel(r.ID)
 	return nil
 }
 
@@ -0,0 +1,200 @@
+package reminder
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"github.com/elizavetanr/myDays/timeutil"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSchedulerRunning = errors.New("планировщик уже запущен")
+	ErrJobNotFound      = errors.New("задание планировщика не найдено")
+)
+
+type job struct {
+	id    string
+	at    time.Time
+	fn    func()
+	index int
+}
+
+// jobQueue - min-куча заданий по времени срабатывания.
+type jobQueue []*job
+
+func (q jobQueue) Len() int { return len(q) }
+
+func (q jobQueue) Less(i, j int) bool { return q[i].at.Before(q[j].at) }
+
+func (q jobQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *jobQueue) Push(x any) {
+	j := x.(*job)
+	j.index = len(*q)
+	*q = append(*q, j)
+}
+
+func (q *jobQueue) Pop() any {
+	old := *q
+	n := len(old)
+	j := old[n-1]
+	old[n-1] = nil
+	j.index = -1
+	*q = old[:n-1]
+	return j
+}
+
+// Scheduler владеет единственным таймером и выполняет задания в своей горутине
+// в порядке их времени срабатывания. Задания можно добавлять до запуска.
+type Scheduler struct {
+	mu      sync.Mutex
+	clock   timeutil.Clock
+	queue   jobQueue
+	jobs    map[string]*job
+	wake    chan struct{}
+	done    chan struct{}
+	started bool
+}
+
+func NewScheduler() *Scheduler {
+	return NewSchedulerWithClock(timeutil.SystemClock)
+}
+
+// NewSchedulerWithClock создает планировщик, который отсчитывает время по clock.
+func NewSchedulerWithClock(clock timeutil.Clock) *Scheduler {
+	return &Scheduler{
+		clock: clock,
+		jobs:  make(map[string]*job),
+		wake:  make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+}
+
+// Start запускает горутину планировщика. Она завершается при отмене ctx.
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return ErrSchedulerRunning
+	}
+	s.started = true
+	go s.run(ctx)
+	return nil
+}
+
+func (s *Scheduler) Now() time.Time {
+	return s.clock.Now()
+}
+
+// Done закрывается после остановки горутины планировщика.
+func (s *Scheduler) Done() <-chan struct{} {
+	return s.done
+}
+
+// Schedule добавляет задание или заменяет уже существующее с тем же id.
+func (s *Scheduler) Schedule(id string, at time.Time, fn func()) {
+	s.mu.Lock()
+	if j, ok := s.jobs[id]; ok {
+		j.at = at
+		j.fn = fn
+		heap.Fix(&s.queue, j.index)
+	} else {
+		j := &job{id: id, at: at, fn: fn}
+		heap.Push(&s.queue, j)
+		s.jobs[id] = j
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+func (s *Scheduler) Reschedule(id string, at time.Time) error {
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrJobNotFound
+	}
+	j.at = at
+	heap.Fix(&s.queue, j.index)
+	s.mu.Unlock()
+	s.notify()
+	return nil
+}
+
+// Cancel удаляет задание и сообщает, было ли оно запланировано.
+func (s *Scheduler) Cancel(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return false
+	}
+	heap.Remove(&s.queue, j.index)
+	delete(s.jobs, id)
+	return true
+}
+
+func (s *Scheduler) IsScheduled(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.jobs[id]
+	return ok
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	defer close(s.done)
+	timer := s.clock.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		due, next, ok := s.popDue(s.clock.Now())
+		for _, j := range due {
+			j.fn()
+		}
+		if len(due) > 0 {
+			continue
+		}
+
+		var wait <-chan time.Time
+		if ok {
+			timer.Reset(next.Sub(s.clock.Now()))
+			wait = timer.C()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-wait:
+		}
+		timer.Stop()
+	}
+}
+
+// popDue извлекает из кучи все задания, время которых наступило,
+// и возвращает время следующего задания.
+func (s *Scheduler) popDue(now time.Time) ([]*job, time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []*job
+	for len(s.queue) > 0 && !s.queue[0].at.After(now) {
+		j := heap.Pop(&s.queue).(*job)
+		delete(s.jobs, j.id)
+		due = append(due, j)
+	}
+	if len(s.queue) == 0 {
+		return due, time.Time{}, false
+	}
+	return due, s.queue[0].at, true
+}
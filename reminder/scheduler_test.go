@@ -0,0 +1,85 @@
+package reminder
+
+import (
+	"context"
+	"github.com/elizavetanr/myDays/timeutil"
+	"testing"
+	"time"
+)
+
+func TestSchedulerRunsJobsInOrder(t *testing.T) {
+	s := NewScheduler()
+	fired := make(chan string, 3)
+	now := time.Now()
+	s.Schedule("second", now.Add(40*time.Millisecond), func() { fired <- "second" })
+	s.Schedule("first", now.Add(20*time.Millisecond), func() { fired <- "first" })
+	s.Schedule("cancelled", now.Add(30*time.Millisecond), func() { fired <- "cancelled" })
+	if !s.Cancel("cancelled") {
+		t.Fatal("Expected job to be cancelled")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx); err != nil {
+		t.Fatalf("Expected no error on start, got %v", err)
+	}
+	if err := s.Start(ctx); err != ErrSchedulerRunning {
+		t.Errorf("Expected ErrSchedulerRunning on second start, got %v", err)
+	}
+
+	for _, expected := range []string{"first", "second"} {
+		select {
+		case id := <-fired:
+			if id != expected {
+				t.Errorf("Expected %s, got %s", expected, id)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %s to fire", expected)
+		}
+	}
+	cancel()
+	<-s.Done()
+}
+
+func TestSchedulerReschedule(t *testing.T) {
+	s := NewScheduler()
+	fired := make(chan struct{}, 1)
+	s.Schedule("job", time.Now().Add(time.Hour), func() { fired <- struct{}{} })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+	if err := s.Reschedule("job", time.Now().Add(10*time.Millisecond)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Expected rescheduled job to fire")
+	}
+	if err := s.Reschedule("job", time.Now()); err != ErrJobNotFound {
+		t.Errorf("Expected ErrJobNotFound for fired job, got %v", err)
+	}
+}
+
+func TestSchedulerUsesClock(t *testing.T) {
+	clock := timeutil.NewFakeClock(time.Date(2025, 10, 11, 9, 0, 0, 0, time.UTC))
+	s := NewSchedulerWithClock(clock)
+	fired := make(chan struct{}, 1)
+	s.Schedule("job", clock.Now().Add(time.Hour), func() { fired <- struct{}{} })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+
+	clock.Advance(59 * time.Minute)
+	if !s.IsScheduled("job") {
+		t.Error("Expected job to wait for the clock")
+	}
+	clock.Advance(time.Minute)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Expected job to fire when the clock reaches it")
+	}
+}
@@ -0,0 +1,312 @@
+package reminder
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/logger"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+var (
+	ErrInvalidSMTPConfig = errors.New("некорректные настройки SMTP")
+	ErrSMTPQueueFull     = errors.New("очередь отправки писем переполнена")
+	ErrSMTPClosed        = errors.New("отправка писем остановлена")
+)
+
+const (
+	TLSNone     = "none"
+	TLSStartTLS = "starttls"
+	TLSImplicit = "tls"
+
+	DefaultEmailSubject = "Напоминание: {{.Title}}"
+	DefaultEmailBody    = "{{.Message}}\n\nСобытие: {{.Title}}\nДата: {{.Date}}\nПриоритет: {{.Priority}}\n"
+	DefaultSMTPBackoff  = 30 * time.Second
+
+	smtpQueueSize = 100
+)
+
+// SMTPConfig - параметры отправки напоминаний по электронной почте.
+// Subject и Body - шаблоны text/template, в которых доступны поля
+// Title, Date, Priority, Message, EventID и ReminderID.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+	TLS      string
+	Subject  string
+	Body     string
+	Retries  int
+	Backoff  time.Duration
+}
+
+type emailData struct {
+	Title      string
+	Date       string
+	Priority   string
+	Message    string
+	EventID    string
+	ReminderID string
+}
+
+type smtpMessage struct {
+	notification Notification
+	data         []byte
+}
+
+// SMTPNotifier отправляет напоминания письмом. Письма отправляются в своей
+// горутине, чтобы медленный почтовый сервер не задерживал остальные
+// напоминания, о каждом отправленном письме сообщает функция из
+// OnDelivered. При ошибке отправка повторяется до Retries раз
+// с экспоненциально растущей паузой, начиная с Backoff.
+type SMTPNotifier struct {
+	name      string
+	cfg       SMTPConfig
+	subject   *template.Template
+	body      *template.Template
+	timeout   time.Duration
+	wait      func(time.Duration) bool
+	mu        sync.Mutex
+	closed    bool
+	delivered func(n Notification)
+	queue     chan smtpMessage
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func NewSMTPNotifier(name string, cfg SMTPConfig) (*SMTPNotifier, error) {
+	if cfg.Host == "" || cfg.Port <= 0 || cfg.From == "" || len(cfg.To) == 0 {
+		return nil, ErrInvalidSMTPConfig
+	}
+	switch cfg.TLS {
+	case "":
+		cfg.TLS = TLSStartTLS
+	case TLSNone, TLSStartTLS, TLSImplicit:
+	default:
+		return nil, fmt.Errorf("tls %q: %w", cfg.TLS, ErrInvalidSMTPConfig)
+	}
+	if cfg.Subject == "" {
+		cfg.Subject = DefaultEmailSubject
+	}
+	if cfg.Body == "" {
+		cfg.Body = DefaultEmailBody
+	}
+	if cfg.Retries < 0 || cfg.Backoff < 0 {
+		return nil, ErrInvalidSMTPConfig
+	}
+	if cfg.Backoff == 0 {
+		cfg.Backoff = DefaultSMTPBackoff
+	}
+	subject, err := template.New("subject").Parse(cfg.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSMTPConfig, err)
+	}
+	body, err := template.New("body").Parse(cfg.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSMTPConfig, err)
+	}
+	s := &SMTPNotifier{
+		name:    name,
+		cfg:     cfg,
+		subject: subject,
+		body:    body,
+		timeout: hookTimeout,
+		queue:   make(chan smtpMessage, smtpQueueSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	s.wait = s.pause
+	go s.run()
+	return s, nil
+}
+
+func (s *SMTPNotifier) Name() string {
+	return s.name
+}
+
+// OnDelivered задает функцию, которая вызывается после отправки письма.
+func (s *SMTPNotifier) OnDelivered(f func(n Notification)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delivered = f
+}
+
+// Notify ставит письмо в очередь на отправку и сразу возвращается.
+func (s *SMTPNotifier) Notify(n Notification) error {
+	msg, err := s.message(n)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSMTPClosed
+	}
+	select {
+	case s.queue <- smtpMessage{notification: n, data: msg}:
+		return nil
+	default:
+		return ErrSMTPQueueFull
+	}
+}
+
+// Close прекращает повторы и дожидается, пока письма из очереди будут
+// отправлены хотя бы по одному разу.
+func (s *SMTPNotifier) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.stop)
+	close(s.queue)
+	s.mu.Unlock()
+	<-s.done
+	return nil
+}
+
+func (s *SMTPNotifier) run() {
+	defer close(s.done)
+	for msg := range s.queue {
+		s.deliver(msg)
+	}
+}
+
+func (s *SMTPNotifier) deliver(msg smtpMessage) {
+	backoff := s.cfg.Backoff
+	for attempt := 0; ; attempt++ {
+		id := msg.notification.ReminderID
+		err := s.send(msg.data)
+		if err == nil {
+			logger.Info(fmt.Sprintf("Письмо с напоминанием %s отправлено: %s", id, strings.Join(s.cfg.To, ", ")))
+			s.mu.Lock()
+			delivered := s.delivered
+			s.mu.Unlock()
+			if delivered != nil {
+				delivered(msg.notification)
+			}
+			return
+		}
+		if attempt >= s.cfg.Retries || !s.wait(backoff) {
+			logger.Error(fmt.Sprintf("Письмо с напоминанием %s не отправлено: %v", id, err))
+			return
+		}
+		logger.Error(fmt.Sprintf("Попытка %d отправки письма с напоминанием %s не удалась: %v", attempt+1, id, err))
+		backoff *= 2
+	}
+}
+
+// pause ждет перед повтором и возвращает false, если отправка остановлена.
+func (s *SMTPNotifier) pause(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-s.stop:
+		return false
+	}
+}
+
+func (s *SMTPNotifier) message(n Notification) ([]byte, error) {
+	data := emailData{
+		Title:      n.Title,
+		Date:       n.StartAt.Format("2006-01-02 15:04"),
+		Priority:   n.Priority,
+		Message:    n.Message,
+		EventID:    n.EventID,
+		ReminderID: n.ReminderID,
+	}
+	var subject, body bytes.Buffer
+	if err := s.subject.Execute(&subject, data); err != nil {
+		return nil, err
+	}
+	if err := s.body.Execute(&body, data); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject.String()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	w := quotedprintable.NewWriter(&msg)
+	if _, err := w.Write(body.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+func (s *SMTPNotifier) send(msg []byte) error {
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host}
+
+	dialer := &net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	// срок на весь сеанс, чтобы зависший сервер не держал очередь писем
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	if s.cfg.TLS == TLSImplicit {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if s.cfg.TLS == TLSStartTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range s.cfg.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
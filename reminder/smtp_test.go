@@ -0,0 +1,269 @@
+package reminder
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer принимает письма по упрощенному протоколу SMTP.
+// Первые failFirst соединений отклоняются с временной ошибкой.
+type fakeSMTPServer struct {
+	listener  net.Listener
+	failFirst int
+	mu        sync.Mutex
+	attempts  int
+	messages  []string
+}
+
+func newFakeSMTPServer(t *testing.T, failFirst int) *fakeSMTPServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error for listener, got %v", err)
+	}
+	s := &fakeSMTPServer{listener: l, failFirst: failFirst}
+	go s.serve()
+	t.Cleanup(func() { l.Close() })
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.attempts++
+		fail := s.attempts <= s.failFirst
+		s.mu.Unlock()
+		if fail {
+			io.WriteString(conn, "421 try again later\r\n")
+			conn.Close()
+			continue
+		}
+		s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	io.WriteString(conn, "220 localhost fake\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			io.WriteString(conn, "250-localhost\r\n250 AUTH PLAIN\r\n")
+		case strings.HasPrefix(cmd, "AUTH"):
+			io.WriteString(conn, "235 ok\r\n")
+		case strings.HasPrefix(cmd, "DATA"):
+			io.WriteString(conn, "354 go ahead\r\n")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			io.WriteString(conn, "250 queued\r\n")
+		case strings.HasPrefix(cmd, "QUIT"):
+			io.WriteString(conn, "221 bye\r\n")
+			return
+		default:
+			io.WriteString(conn, "250 ok\r\n")
+		}
+	}
+}
+
+func TestSMTPNotifierSendsTemplatedEmail(t *testing.T) {
+	server := newFakeSMTPServer(t, 0)
+	n, err := NewSMTPNotifier("email", SMTPConfig{
+		Host:     "localhost",
+		Port:     server.port(),
+		Username: "user",
+		Password: "secret",
+		From:     "mydays@example.com",
+		To:       []string{"me@example.com"},
+		TLS:      TLSNone,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error for config, got %v", err)
+	}
+	err = n.Notify(Notification{
+		Title:    "Планерка",
+		StartAt:  time.Date(2030, 10, 11, 15, 0, 0, 0, time.Local),
+		Priority: "high",
+		Message:  "Скоро планерка",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error for notify, got %v", err)
+	}
+	n.Close()
+	messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("Expected one message, got %d", len(messages))
+	}
+	_, body, _ := strings.Cut(messages[0], "\r\n\r\n")
+	decoded, _ := io.ReadAll(quotedprintable.NewReader(strings.NewReader(body)))
+	for _, expected := range []string{"Скоро планерка", "Событие: Планерка", "Дата: 2030-10-11 15:00", "Приоритет: high"} {
+		if !strings.Contains(string(decoded), expected) {
+			t.Errorf("Expected body to contain %q, got %q", expected, decoded)
+		}
+	}
+}
+
+func TestSMTPNotifierRetriesWithBackoff(t *testing.T) {
+	server := newFakeSMTPServer(t, 2)
+	n, _ := NewSMTPNotifier("email", SMTPConfig{
+		Host:    "localhost",
+		Port:    server.port(),
+		From:    "mydays@example.com",
+		To:      []string{"me@example.com"},
+		TLS:     TLSNone,
+		Retries: 3,
+		Backoff: time.Second,
+	})
+	var pauses []time.Duration
+	n.wait = func(d time.Duration) bool {
+		pauses = append(pauses, d)
+		return true
+	}
+
+	if err := n.Notify(Notification{Title: "Планерка"}); err != nil {
+		t.Fatalf("Expected message to be queued, got %v", err)
+	}
+	n.Close()
+	if len(server.received()) != 1 {
+		t.Errorf("Expected delivery after retries, got %d messages", len(server.received()))
+	}
+	if len(pauses) != 2 || pauses[0] != time.Second || pauses[1] != 2*time.Second {
+		t.Errorf("Expected backoff 1s, 2s, got %v", pauses)
+	}
+	if err := n.Notify(Notification{Title: "Планерка"}); !errors.Is(err, ErrSMTPClosed) {
+		t.Errorf("Expected ErrSMTPClosed after Close, got %v", err)
+	}
+}
+
+func TestSMTPNotifierDefaultBackoff(t *testing.T) {
+	n, err := NewSMTPNotifier("email", SMTPConfig{Host: "localhost", Port: 25, From: "a@example.com", To: []string{"b@example.com"}})
+	if err != nil {
+		t.Fatalf("Expected no error for config, got %v", err)
+	}
+	defer n.Close()
+	if n.cfg.Backoff != DefaultSMTPBackoff {
+		t.Errorf("Expected default backoff %v, got %v", DefaultSMTPBackoff, n.cfg.Backoff)
+	}
+}
+
+// TestSMTPNotifierDoesNotBlock проверяет, что зависший сервер не задерживает
+// вызывающего: Notify возвращается сразу, а сеанс прерывается по таймауту.
+func TestSMTPNotifierDoesNotBlock(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error for listener, got %v", err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			// соединение принято, но приветствие сервер не отправляет
+			defer conn.Close()
+		}
+	}()
+	n, _ := NewSMTPNotifier("email", SMTPConfig{
+		Host:    "localhost",
+		Port:    l.Addr().(*net.TCPAddr).Port,
+		From:    "mydays@example.com",
+		To:      []string{"me@example.com"},
+		TLS:     TLSNone,
+		Retries: 5,
+		Backoff: time.Hour,
+	})
+	n.timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	if err := n.Notify(Notification{Title: "Планерка"}); err != nil {
+		t.Fatalf("Expected message to be queued, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("Expected Notify to return immediately, took %v", elapsed)
+	}
+	closed := make(chan struct{})
+	go func() {
+		n.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Close to stop retries and time out the session")
+	}
+}
+
+// TestSMTPNotifierReportsOnlySentEmails проверяет, что письмо считается
+// доставленным только после отправки, а не при постановке в очередь.
+func TestSMTPNotifierReportsOnlySentEmails(t *testing.T) {
+	for _, test := range []struct {
+		failFirst int
+		delivered int
+	}{
+		{0, 1},
+		{10, 0},
+	} {
+		server := newFakeSMTPServer(t, test.failFirst)
+		n, _ := NewSMTPNotifier("email", SMTPConfig{
+			Host: "localhost",
+			Port: server.port(),
+			From: "mydays@example.com",
+			To:   []string{"me@example.com"},
+			TLS:  TLSNone,
+		})
+		r := NewRegistry()
+		r.Register(n)
+		r.SetDefaultRoute("email")
+		var mu sync.Mutex
+		var reported []string
+		r.OnDelivered(func(n Notification, name string) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, n.ReminderID+"/"+name)
+		})
+
+		delivered, err := r.Deliver(Notification{ReminderID: "r1", Title: "Планерка"})
+		if err != nil || len(delivered) != 0 {
+			t.Errorf("Expected queued email not to count as delivered, got %v (%v)", delivered, err)
+		}
+		r.Close()
+		if len(reported) != test.delivered || test.delivered == 1 && reported[0] != "r1/email" {
+			t.Errorf("Expected %d reported deliveries, got %v", test.delivered, reported)
+		}
+	}
+}
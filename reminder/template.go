@@ -0,0 +1,60 @@
+package reminder
+
+import (
+	"errors"
+	"strings"
+	"text/template"
+)
+
+var (
+	ErrInvalidTemplate = errors.New("некорректный шаблон напоминания")
+)
+
+// DefaultTemplate используется для напоминаний с пустым текстом.
+const DefaultTemplate = "{{.Title}} - через {{.Until}} ({{.Priority}})"
+
+// TemplateData - значения, доступные в шаблоне текста напоминания.
+// Until - время, оставшееся до начала события в момент отправки.
+type TemplateData struct {
+	Title    string
+	Priority string
+	Date     string
+	Until    string
+}
+
+// ValidateTemplate проверяет синтаксис шаблона и используемые в нем поля.
+func ValidateTemplate(message string) error {
+	t, err := parseTemplate(message)
+	if err != nil {
+		return err
+	}
+	if err := t.Execute(&strings.Builder{}, TemplateData{}); err != nil {
+		return ErrInvalidTemplate
+	}
+	return nil
+}
+
+// RenderMessage подставляет значения в шаблон текста напоминания. Если шаблон
+// не удалось применить, текст возвращается как есть.
+func RenderMessage(message string, data TemplateData) string {
+	t, err := parseTemplate(message)
+	if err != nil {
+		return message
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return message
+	}
+	return b.String()
+}
+
+func parseTemplate(message string) (*template.Template, error) {
+	if message == "" {
+		message = DefaultTemplate
+	}
+	t, err := template.New("reminder").Parse(message)
+	if err != nil {
+		return nil, ErrInvalidTemplate
+	}
+	return t, nil
+}
@@ -0,0 +1,30 @@
+package reminder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRenderMessage(t *testing.T) {
+	data := TemplateData{Title: "Планерка", Priority: "high", Date: "2025-10-11 10:00", Until: "1h"}
+	if msg := RenderMessage("{{.Title}} начнется через {{.Until}} ({{.Priority}})", data); msg != "Планерка начнется через 1h (high)" {
+		t.Errorf("Expected rendered template, got %q", msg)
+	}
+	if msg := RenderMessage("", data); msg != "Планерка - через 1h (high)" {
+		t.Errorf("Expected default template for empty message, got %q", msg)
+	}
+	if msg := RenderMessage("Просто текст", data); msg != "Просто текст" {
+		t.Errorf("Expected plain text unchanged, got %q", msg)
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	for _, message := range []string{"{{.Title", "{{.Place}}"} {
+		if err := ValidateTemplate(message); !errors.Is(err, ErrInvalidTemplate) {
+			t.Errorf("Expected ErrInvalidTemplate for %q, got %v", message, err)
+		}
+	}
+	if err := ValidateTemplate("{{.Title}} в {{.Date}}"); err != nil {
+		t.Errorf("Expected valid template, got %v", err)
+	}
+}
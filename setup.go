@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/elizavetanr/myDays/calendar"
+	"github.com/elizavetanr/myDays/config"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/reminder"
+	"github.com/elizavetanr/myDays/timeutil"
+	"time"
+)
+
+var (
+	ErrUnknownNotifierType = errors.New("неизвестный тип способа доставки")
+)
+
+// configure применяет настройки к календарю и возвращает ошибки
+// некорректных параметров. Остальные параметры применяются в любом случае.
+func configure(c *calendar.Calendar, cfg *config.Config) []error {
+	var errs []error
+	if err := c.SetConflictPolicy(calendar.ConflictPolicy(cfg.ConflictPolicy)); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.SetDefaultDuration(time.Duration(cfg.DefaultDuration)); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.SetGraceWindow(time.Duration(cfg.GraceWindow)); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.SetRepeatInterval(time.Duration(cfg.RepeatInterval)); err != nil {
+		errs = append(errs, err)
+	}
+	if hours, err := calendar.ParseWorkingHours(cfg.WorkingHours); err != nil {
+		errs = append(errs, err)
+	} else {
+		c.SetWorkingHours(hours)
+	}
+	if cfg.QuietHours != "" {
+		if quiet, err := reminder.ParseQuietHours(cfg.QuietHours); err != nil {
+			errs = append(errs, err)
+		} else {
+			c.DoNotDisturb().SetQuietHours(&quiet)
+		}
+	}
+	c.DoNotDisturb().SetBreakthrough(cfg.QuietBreakthrough)
+	policy := make(map[events.Priority][]time.Duration)
+	for priority, offsets := range cfg.DefaultReminders {
+		for _, offset := range offsets {
+			policy[events.Priority(priority)] = append(policy[events.Priority(priority)], time.Duration(offset))
+		}
+	}
+	if err := c.SetDefaultReminders(policy); err != nil {
+		errs = append(errs, err)
+	}
+	if cfg.DigestTime != "" {
+		if at, err := timeutil.ParseClock(cfg.DigestTime); err != nil {
+			errs = append(errs, err)
+		} else if err := c.SetDigest(at, cfg.DigestIncludeTomorrow); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return append(errs, configureNotifiers(c.Notifiers(), cfg)...)
+}
+
+func configureNotifiers(registry *reminder.Registry, cfg *config.Config) []error {
+	var errs []error
+	for _, nc := range cfg.Notifiers {
+		n, err := newNotifier(nc)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := registry.Register(n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for priority, names := range cfg.Routes {
+		var err error
+		if priority == "default" {
+			err = registry.SetDefaultRoute(names...)
+		} else if err = events.Priority(priority).Validate(); err != nil {
+			err = fmt.Errorf("маршрут %q: %w", priority, err)
+		} else {
+			err = registry.Route(priority, names...)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func newNotifier(nc config.NotifierConfig) (reminder.Notifier, error) {
+	switch nc.Type {
+	case "command":
+		return reminder.NewCommandNotifier(nc.Name, nc.Command, nc.Args...), nil
+	case "webhook":
+		return reminder.NewWebhookNotifier(nc.Name, nc.URL), nil
+	case "file":
+		return reminder.NewFileNotifier(nc.Name, nc.Path), nil
+	case "smtp":
+		if nc.SMTP == nil {
+			return nil, fmt.Errorf("%s: %w", nc.Name, reminder.ErrInvalidSMTPConfig)
+		}
+		return reminder.NewSMTPNotifier(nc.Name, reminder.SMTPConfig{
+			Host:     nc.SMTP.Host,
+			Port:     nc.SMTP.Port,
+			Username: nc.SMTP.Username,
+			Password: nc.SMTP.Password,
+			From:     nc.SMTP.From,
+			To:       nc.SMTP.To,
+			TLS:      nc.SMTP.TLS,
+			Subject:  nc.SMTP.Subject,
+			Body:     nc.SMTP.Body,
+			Retries:  nc.SMTP.Retries,
+			Backoff:  time.Duration(nc.SMTP.Backoff),
+		})
+	}
+	return nil, fmt.Errorf("%s: %w", nc.Type, ErrUnknownNotifierType)
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+	"github.com/elizavetanr/myDays/config"
+	"github.com/elizavetanr/myDays/events"
+	"github.com/elizavetanr/myDays/reminder"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigureNotifiersRejectsUnknownPriority(t *testing.T) {
+	registry := reminder.NewRegistry()
+	cfg := &config.Config{
+		Notifiers: []config.NotifierConfig{{Name: "log", Type: "file", Path: filepath.Join(t.TempDir(), "log.txt")}},
+		Routes: map[string][]string{
+			"high":    {"log"},
+			"hihg":    {"log"},
+			"default": {"log"},
+		},
+	}
+	errs := configureNotifiers(registry, cfg)
+	if len(errs) != 1 || !errors.Is(errs[0], events.ErrInvalidPriority) {
+		t.Fatalf("Expected one ErrInvalidPriority, got %v", errs)
+	}
+	if targets := registry.Targets("high"); len(targets) != 1 || targets[0].Name() != "log" {
+		t.Errorf("Expected valid route to be applied, got %v", targets)
+	}
+}
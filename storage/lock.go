@@ -0,0 +1,27 @@
+package storage
+
+import "errors"
+
+var (
+	ErrLocked = errors.New("файл заблокирован другим процессом")
+)
+
+// Lock - блокировка файла, которую держит запущенный экземпляр приложения.
+// Блокировка снимается при Unlock или при завершении процесса.
+type Lock struct {
+	release func() error
+}
+
+// TryLock захватывает блокировку файла path, не дожидаясь ее освобождения.
+// Если блокировку держит другой процесс, возвращается ErrLocked.
+func TryLock(path string) (*Lock, error) {
+	release, err := tryLock(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Lock{release: release}, nil
+}
+
+func (l *Lock) Unlock() error {
+	return l.release()
+}
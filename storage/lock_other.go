@@ -0,0 +1,8 @@
+//go:build !unix
+
+package storage
+
+// на других системах блокировка не поддерживается и всегда считается захваченной
+func tryLock(path string) (func() error, error) {
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,270 @@
+package timers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidFormat = errors.New("некорректный формат таймеров")
+	ErrExportFailed  = errors.New("экспорт таймеров не выполнен")
+)
+
+// Format - вид генерируемых заданий: пользовательские таймеры systemd
+// или задания для at.
+type Format string
+
+const (
+	FormatSystemd Format = "systemd"
+	FormatAt      Format = "at"
+)
+
+const prefix = "mydays-"
+
+func (f Format) Validate() error {
+	switch f {
+	case FormatSystemd, FormatAt:
+		return nil
+	default:
+		return ErrInvalidFormat
+	}
+}
+
+// Job - напоминание, которое нужно отправить в момент At.
+type Job struct {
+	ReminderID string
+	Title      string
+	Message    string
+	At         time.Time
+}
+
+// Result описывает изменения в каталоге заданий после синхронизации.
+type Result struct {
+	Written []string
+	Removed []string
+}
+
+// Exporter сохраняет задания в Dir так, чтобы каждое в свое время запускало
+// Command с ID напоминания последним аргументом. Задания, которых больше нет
+// среди переданных, удаляются. Если Activate включен, таймеры systemd
+// включаются и выключаются через systemctl --user.
+type Exporter struct {
+	Format   Format
+	Dir      string
+	Command  []string
+	WorkDir  string
+	Activate bool
+	run      func(name string, args ...string) error
+}
+
+func NewExporter(format Format, dir string, command []string, workDir string) (*Exporter, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+	return &Exporter{
+		Format:  format,
+		Dir:     dir,
+		Command: command,
+		WorkDir: workDir,
+		run: func(name string, args ...string) error {
+			output, err := exec.Command(name, args...).CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+			}
+			return nil
+		},
+	}, nil
+}
+
+// DefaultDir возвращает каталог пользовательских юнитов systemd
+// или каталог timers в текущем каталоге для заданий at.
+func DefaultDir(format Format) (string, error) {
+	if format == FormatAt {
+		return "timers", nil
+	}
+	config, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(config, "systemd", "user"), nil
+}
+
+// Sync приводит файлы заданий в Dir к списку jobs. Очередь at не меняется:
+// задания, уже поставленные в нее, не удаляются, а новые ставятся командами
+// из AtCommands. Таймеры systemd перечитываются и включаются только при
+// включенном Activate.
+func (e *Exporter) Sync(jobs []Job) (*Result, error) {
+	if err := os.MkdirAll(e.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExportFailed, err)
+	}
+	existing, err := e.existing()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExportFailed, err)
+	}
+
+	result := &Result{}
+	current := make(map[string]bool)
+	for _, job := range jobs {
+		current[job.ReminderID] = true
+		files, err := e.write(job)
+		if err != nil {
+			return result, fmt.Errorf("%w: %w", ErrExportFailed, err)
+		}
+		result.Written = append(result.Written, files...)
+	}
+	var stale []string
+	for id := range existing {
+		if !current[id] {
+			stale = append(stale, id)
+		}
+	}
+	sort.Strings(stale)
+	for _, id := range stale {
+		if e.Activate && e.Format == FormatSystemd {
+			e.run("systemctl", "--user", "disable", "--now", unitName(id)+".timer")
+		}
+		for _, file := range existing[id] {
+			if err := os.Remove(file); err != nil {
+				return result, fmt.Errorf("%w: %w", ErrExportFailed, err)
+			}
+			result.Removed = append(result.Removed, file)
+		}
+	}
+	if e.Activate && e.Format == FormatSystemd {
+		if err := e.activate(jobs); err != nil {
+			return result, fmt.Errorf("%w: %w", ErrExportFailed, err)
+		}
+	}
+	return result, nil
+}
+
+// AtCommands возвращает команды at, ставящие задания в очередь.
+func (e *Exporter) AtCommands(jobs []Job) []string {
+	commands := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		commands = append(commands, fmt.Sprintf("at -t %s -f %s",
+			job.At.Format("200601021504"), quote(filepath.Join(e.Dir, unitName(job.ReminderID)+".at"))))
+	}
+	return commands
+}
+
+func (e *Exporter) activate(jobs []Job) error {
+	if err := e.run("systemctl", "--user", "daemon-reload"); err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if err := e.run("systemctl", "--user", "enable", "--now", unitName(job.ReminderID)+".timer"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) write(job Job) ([]string, error) {
+	files := map[string]string{}
+	switch e.Format {
+	case FormatSystemd:
+		files[unitName(job.ReminderID)+".timer"] = e.timerUnit(job)
+		files[unitName(job.ReminderID)+".service"] = e.serviceUnit(job)
+	case FormatAt:
+		files[unitName(job.ReminderID)+".at"] = e.atScript(job)
+	}
+	var written []string
+	for name, content := range files {
+		path := filepath.Join(e.Dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	sort.Strings(written)
+	return written, nil
+}
+
+func (e *Exporter) timerUnit(job Job) string {
+	return fmt.Sprintf("[Unit]\nDescription=%s\n\n[Timer]\nOnCalendar=%s\nPersistent=true\nUnit=%s.service\n\n[Install]\nWantedBy=timers.target\n",
+		escapeSystemd("myDays: "+job.Title+" - "+job.Message), job.At.Format("2006-01-02 15:04:05"), unitName(job.ReminderID))
+}
+
+func (e *Exporter) serviceUnit(job Job) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\nDescription=%s\n\n[Service]\nType=oneshot\n", escapeSystemd("myDays: "+job.Title))
+	if e.WorkDir != "" {
+		fmt.Fprintf(&b, "WorkingDirectory=%s\n", escapeSystemd(e.WorkDir))
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", escapeSystemd(e.commandLine(job)))
+	return b.String()
+}
+
+func (e *Exporter) atScript(job Job) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# myDays: %s\n# at -t %s -f %s\n", escapeComment(job.Title+" - "+job.Message),
+		job.At.Format("200601021504"), unitName(job.ReminderID)+".at")
+	if e.WorkDir != "" {
+		fmt.Fprintf(&b, "cd %s || exit 1\n", quote(e.WorkDir))
+	}
+	b.WriteString(e.commandLine(job) + "\n")
+	return b.String()
+}
+
+func (e *Exporter) commandLine(job Job) string {
+	parts := make([]string, 0, len(e.Command)+1)
+	for _, part := range append(slices.Clone(e.Command), job.ReminderID) {
+		parts = append(parts, quote(part))
+	}
+	return strings.Join(parts, " ")
+}
+
+// existing возвращает ранее созданные файлы заданий по ID напоминания.
+func (e *Exporter) existing() (map[string][]string, error) {
+	entries, err := os.ReadDir(e.Dir)
+	if err != nil {
+		return nil, err
+	}
+	extensions := []string{".timer", ".service"}
+	if e.Format == FormatAt {
+		extensions = []string{".at"}
+	}
+	files := make(map[string][]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		if !strings.HasPrefix(name, prefix) || !slices.Contains(extensions, ext) {
+			continue
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		files[id] = append(files[id], filepath.Join(e.Dir, name))
+	}
+	return files, nil
+}
+
+func unitName(reminderID string) string {
+	return prefix + reminderID
+}
+
+// quote заключает аргумент в одинарные кавычки, если в нем есть
+// символы, которые оболочка или systemd могут понять иначе.
+func quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`;&|<>()*?[]#~") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func escapeSystemd(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// escapeComment убирает переводы строк, чтобы текст не вышел за пределы
+// комментария в скрипте оболочки.
+func escapeComment(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
@@ -0,0 +1,96 @@
+package timers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyncWritesSystemdUnitsAndRemovesStale(t *testing.T) {
+	dir := t.TempDir()
+	e, err := NewExporter(FormatSystemd, dir, []string{"/opt/my days/myDays", "notify"}, "/home/user")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var commands []string
+	e.run = func(name string, args ...string) error {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		return nil
+	}
+	at := time.Date(2025, 10, 11, 9, 0, 0, 0, time.Local)
+	os.WriteFile(filepath.Join(dir, "mydays-old.timer"), nil, 0644)
+	os.WriteFile(filepath.Join(dir, "other.timer"), nil, 0644)
+
+	result, err := e.Sync([]Job{{ReminderID: "r1", Title: "Планерка", Message: "через 100%", At: at}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Written) != 2 || len(result.Removed) != 1 {
+		t.Errorf("Expected two units written and one removed, got %+v", result)
+	}
+	timer, _ := os.ReadFile(filepath.Join(dir, "mydays-r1.timer"))
+	if !strings.Contains(string(timer), "OnCalendar=2025-10-11 09:00:00") || !strings.Contains(string(timer), "через 100%%") {
+		t.Errorf("Expected timer unit with calendar time and escaped description, got %q", timer)
+	}
+	service, _ := os.ReadFile(filepath.Join(dir, "mydays-r1.service"))
+	if !strings.Contains(string(service), "ExecStart='/opt/my days/myDays' notify r1") ||
+		!strings.Contains(string(service), "WorkingDirectory=/home/user") {
+		t.Errorf("Expected service unit running notify, got %q", service)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other.timer")); err != nil {
+		t.Error("Expected foreign unit to be kept")
+	}
+	if len(commands) != 0 {
+		t.Errorf("Expected systemctl not to run without Activate, got %v", commands)
+	}
+
+	e.Activate = true
+	result, _ = e.Sync(nil)
+	if len(result.Removed) != 2 {
+		t.Errorf("Expected units of removed reminder to be deleted, got %v", result.Removed)
+	}
+	if len(commands) != 2 || commands[0] != "systemctl --user disable --now mydays-r1.timer" {
+		t.Errorf("Expected stale timer to be disabled, got %v", commands)
+	}
+}
+
+func TestSyncWritesAtJobs(t *testing.T) {
+	dir := t.TempDir()
+	e, _ := NewExporter(FormatAt, dir, []string{"myDays", "notify"}, "")
+	jobs := []Job{{ReminderID: "r1", Title: "Планерка", At: time.Date(2025, 10, 11, 9, 0, 0, 0, time.Local)}}
+	if _, err := e.Sync(jobs); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	script, _ := os.ReadFile(filepath.Join(dir, "mydays-r1.at"))
+	if !strings.HasSuffix(string(script), "\nmyDays notify r1\n") {
+		t.Errorf("Expected at job running notify, got %q", script)
+	}
+	if commands := e.AtCommands(jobs); len(commands) != 1 || !strings.HasPrefix(commands[0], "at -t 202510110900 -f ") {
+		t.Errorf("Expected at command, got %v", commands)
+	}
+	if _, err := NewExporter("cron", dir, nil, ""); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Expected ErrInvalidFormat, got %v", err)
+	}
+}
+
+func TestAtJobKeepsMultilineMessageInComment(t *testing.T) {
+	dir := t.TempDir()
+	e, _ := NewExporter(FormatAt, dir, []string{"myDays", "notify"}, "")
+	job := Job{ReminderID: "r1", Title: "Планерка", Message: "Скоро планерка\nrm -rf ~\r\ntouch pwned", At: time.Date(2025, 10, 11, 9, 0, 0, 0, time.Local)}
+	if _, err := e.Sync([]Job{job}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	script, _ := os.ReadFile(filepath.Join(dir, "mydays-r1.at"))
+	lines := strings.Split(strings.TrimSuffix(string(script), "\n"), "\n")
+	if len(lines) != 3 || lines[0] != "# myDays: Планерка - Скоро планерка rm -rf ~ touch pwned" {
+		t.Errorf("Expected message on a single comment line, got %q", script)
+	}
+	for _, line := range lines[:2] {
+		if !strings.HasPrefix(line, "#") {
+			t.Errorf("Expected only comments before the command, got %q", line)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package timeutil
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock - источник текущего времени и таймеров. В тестах вместо системных
+// часов используется FakeClock, время которого переводится вручную.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer повторяет поведение time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// SystemClock - системные часы.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// FakeClock - часы, которые идут только при вызове Advance или Set.
+// Таймеры, чье время наступило, срабатывают в момент перевода часов.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, t)
+	t.reset(d)
+	return t
+}
+
+// Advance переводит часы вперед на d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.fireDue()
+}
+
+// Set переводит часы на момент t.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+	c.fireDue()
+}
+
+func (c *FakeClock) fireDue() {
+	for _, t := range c.timers {
+		if t.active && !t.deadline.After(c.now) {
+			t.fire()
+		}
+	}
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	c        chan time.Time
+	deadline time.Time
+	active   bool
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	wasActive := t.active
+	t.active = false
+	return wasActive
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.reset(d)
+}
+
+// reset вызывается под блокировкой часов. Как и у time.Timer, несчитанное
+// срабатывание сбрасывается, а таймер с уже наступившим временем срабатывает сразу.
+func (t *fakeTimer) reset(d time.Duration) bool {
+	wasActive := t.active
+	select {
+	case <-t.c:
+	default:
+	}
+	t.deadline = t.clock.now.Add(d)
+	t.active = true
+	if !t.deadline.After(t.clock.now) {
+		t.fire()
+	}
+	return wasActive
+}
+
+func (t *fakeTimer) fire() {
+	t.active = false
+	select {
+	case t.c <- t.clock.now:
+	default:
+	}
+}
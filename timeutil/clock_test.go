@@ -0,0 +1,42 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClockFiresTimersOnAdvance(t *testing.T) {
+	start := time.Date(2025, 10, 11, 9, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	timer := clock.NewTimer(time.Hour)
+
+	clock.Advance(30 * time.Minute)
+	select {
+	case <-timer.C():
+		t.Fatal("Expected timer not to fire before its time")
+	default:
+	}
+
+	clock.Advance(30 * time.Minute)
+	select {
+	case at := <-timer.C():
+		if !at.Equal(start.Add(time.Hour)) {
+			t.Errorf("Expected timer to fire at %v, got %v", start.Add(time.Hour), at)
+		}
+	default:
+		t.Fatal("Expected timer to fire")
+	}
+
+	if timer.Reset(time.Minute) {
+		t.Error("Expected fired timer to be inactive")
+	}
+	if !timer.Stop() {
+		t.Error("Expected reset timer to be active")
+	}
+	clock.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Error("Expected stopped timer not to fire")
+	default:
+	}
+}
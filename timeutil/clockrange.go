@@ -0,0 +1,64 @@
+package timeutil
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidClockRange = errors.New("некорректный интервал времени суток")
+	ErrInvalidClock      = errors.New("некорректное время суток")
+)
+
+// ParseClockRange разбирает интервал времени суток вида "09:00-18:00" и возвращает
+// его границы как смещения от начала суток. Конец может быть раньше начала,
+// если интервал переходит через полночь.
+func ParseClockRange(s string) (time.Duration, time.Duration, error) {
+	var startH, startM, endH, endM int
+	if _, err := fmt.Sscanf(s, "%d:%d-%d:%d", &startH, &startM, &endH, &endM); err != nil {
+		return 0, 0, ErrInvalidClockRange
+	}
+	if !validClock(startH, startM) || !validClock(endH, endM) {
+		return 0, 0, ErrInvalidClockRange
+	}
+	start := time.Duration(startH)*time.Hour + time.Duration(startM)*time.Minute
+	end := time.Duration(endH)*time.Hour + time.Duration(endM)*time.Minute
+	return start, end, nil
+}
+
+// ParseClock разбирает время суток вида "08:00" и возвращает смещение от начала суток.
+func ParseClock(s string) (time.Duration, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || !validClock(h, m) || h == 24 {
+		return 0, ErrInvalidClock
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+func FormatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// AtClock возвращает момент, когда на часах в день t будет время суток clock.
+// В дни перехода на летнее время и обратно он отличается от StartOfDay(t).Add(clock).
+func AtClock(t time.Time, clock time.Duration) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, int(clock/time.Minute), 0, 0, t.Location())
+}
+
+// ClockOf возвращает время суток t по часам как смещение от полуночи.
+func ClockOf(t time.Time) time.Duration {
+	hour, minute, second := t.Clock()
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second + time.Duration(t.Nanosecond())
+}
+
+func validClock(h, m int) bool {
+	return h >= 0 && m >= 0 && m < 60 && (h < 24 || h == 24 && m == 0)
+}
@@ -0,0 +1,25 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestAtClockOnDaylightSavingDay(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// 26 октября 2025 года в Берлине часы переводятся с 03:00 на 02:00
+	day := time.Date(2025, 10, 26, 12, 0, 0, 0, berlin)
+	if at := AtClock(day, 9*time.Hour); !at.Equal(time.Date(2025, 10, 26, 9, 0, 0, 0, berlin)) {
+		t.Errorf("Expected 09:00 by the wall clock, got %v", at)
+	}
+	if at := AtClock(day, 24*time.Hour); !at.Equal(time.Date(2025, 10, 27, 0, 0, 0, 0, berlin)) {
+		t.Errorf("Expected midnight of the next day, got %v", at)
+	}
+	if clock := ClockOf(day); clock != 12*time.Hour {
+		t.Errorf("Expected 12:00, got %v", FormatClock(clock))
+	}
+}
@@ -0,0 +1,77 @@
+package timeutil
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidDuration = errors.New("некорректный формат интервала")
+)
+
+var durationPart = regexp.MustCompile(`(\d+(?:\.\d+)?)(w|d|h|ms|us|µs|ns|m|s)`)
+
+var units = map[string]time.Duration{
+	"w":  7 * 24 * time.Hour,
+	"d":  24 * time.Hour,
+	"h":  time.Hour,
+	"m":  time.Minute,
+	"s":  time.Second,
+	"ms": time.Millisecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ns": time.Nanosecond,
+}
+
+// ParseDuration расширяет time.ParseDuration единицами "d" (сутки) и "w" (неделя):
+// допустимы значения вида "2w", "1d2h", "1.5h" или "90m".
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidDuration
+	}
+	matches := durationPart.FindAllStringSubmatchIndex(s, -1)
+	var total time.Duration
+	pos := 0
+	for _, m := range matches {
+		if m[0] != pos {
+			return 0, ErrInvalidDuration
+		}
+		value, err := strconv.ParseFloat(s[m[2]:m[3]], 64)
+		if err != nil {
+			return 0, ErrInvalidDuration
+		}
+		total += time.Duration(value * float64(units[s[m[4]:m[5]]]))
+		pos = m[1]
+	}
+	if pos != len(s) {
+		return 0, ErrInvalidDuration
+	}
+	return total, nil
+}
+
+// FormatDuration выводит интервал в том же формате, который принимает
+// ParseDuration: "1d2h30m". Доли секунды отбрасываются.
+func FormatDuration(d time.Duration) string {
+	var b strings.Builder
+	if d < 0 {
+		b.WriteString("-")
+		d = -d
+	}
+	for _, unit := range []struct {
+		name string
+		size time.Duration
+	}{{"d", 24 * time.Hour}, {"h", time.Hour}, {"m", time.Minute}, {"s", time.Second}} {
+		if n := d / unit.size; n > 0 {
+			b.WriteString(strconv.FormatInt(int64(n), 10) + unit.name)
+			d -= n * unit.size
+		}
+	}
+	if b.Len() == 0 || b.String() == "-" {
+		return "0s"
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"1d":     24 * time.Hour,
+		"2w":     14 * 24 * time.Hour,
+		"1d2h":   26 * time.Hour,
+		"1.5h":   90 * time.Minute,
+		"2h45m":  2*time.Hour + 45*time.Minute,
+		"120m":   2 * time.Hour,
+		"1w1d1m": 8*24*time.Hour + time.Minute,
+	}
+	for input, expected := range cases {
+		d, err := ParseDuration(input)
+		if err != nil || d != expected {
+			t.Errorf("Expected %v for %q, got %v (%v)", expected, input, d, err)
+		}
+	}
+	for _, input := range []string{"", "d", "1x", "1d 2h", "-1h", "2025-10-11"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("Expected an error for %q, got none", input)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	cases := map[time.Duration]string{
+		0:                                  "0s",
+		90 * time.Minute:                   "1h30m",
+		26 * time.Hour:                     "1d2h",
+		14 * 24 * time.Hour:                "14d",
+		-(time.Hour + 5*time.Second):       "-1h5s",
+		time.Minute + 500*time.Millisecond: "1m",
+	}
+	for d, expected := range cases {
+		if s := FormatDuration(d); s != expected {
+			t.Errorf("Expected %q for %v, got %q", expected, d, s)
+		}
+	}
+}